@@ -3,11 +3,14 @@ package argsieve
 import (
 	"encoding"
 	"errors"
+	"flag"
 	"fmt"
-	"iter"
+	"io"
+	"io/fs"
 	"reflect"
-	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // ErrParse indicates a parsing error such as a missing value for a flag
@@ -20,40 +23,149 @@ import (
 //	}
 var ErrParse = errors.New("argument parsing error")
 
+// ErrSchema indicates an invalid options struct, such as a tagged field
+// with an unsupported type. Functions that take a target panic on schema
+// errors; [ParseAs] and [SiftAs] return them wrapped with ErrSchema.
+var ErrSchema = errors.New("invalid options schema")
+
 // textUnmarshalerType is used to check if a type implements encoding.TextUnmarshaler.
 var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 
+// textMarshalerType is used to check if a type implements encoding.TextMarshaler.
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
 // Config holds optional settings for argument parsing.
 // Pass nil to use defaults.
 type Config struct {
 	// RequirePositionalDelimiter when true requires all positional arguments
-	// to appear after the "--" delimiter. Positional arguments before "--"
-	// will cause a parse error.
+	// to appear after the "--" delimiter (or one of Delimiters). Positional
+	// arguments before it will cause a parse error.
 	RequirePositionalDelimiter bool
 
 	// StopAtFirstPositional when true stops flag parsing at the first
 	// positional argument. All subsequent arguments are treated as positional,
 	// even if they look like flags.
 	StopAtFirstPositional bool
+
+	// SingleDashLong when true also accepts long flags written with a single
+	// dash (-name, -name value, -name=value), as in the standard flag
+	// package. A single-dash argument is matched against long names and
+	// passthrough flags first, and falls back to short flag parsing when its
+	// first character is a known short flag. An argument with "=" falls back
+	// only if a short flag in the chain takes the value (-ofile=x). Other
+	// unknown single-dash arguments are passed through verbatim instead of
+	// being split into individual characters.
+	SingleDashLong bool
+
+	// MultiCharShort when true allows short tags longer than one character
+	// (e.g. `short:"nc"` for -nc). Short flag arguments are matched against
+	// the longest declared short name at each position before falling back
+	// to single-character flags, so "-ncv" parses as -nc -v.
+	MultiCharShort bool
+
+	// NegativeNumbersPositional when true treats arguments that parse as
+	// negative numbers (e.g. -5, -0.25) as positional instead of flags.
+	// A field tagged `numeric:"true"` still takes precedence for -<digits>.
+	NegativeNumbersPositional bool
+
+	// ResponseFiles when true expands @path arguments into the arguments
+	// read from the file at path before parsing, like GCC and MSVC response
+	// files. Arguments in the file are separated by whitespace; single or
+	// double quotes group text and a backslash escapes the next character.
+	// Response files may include other response files. An @path naming a
+	// file that does not exist is kept as a literal argument. Options
+	// structs with fields tagged `fromfile:"true"` cannot use it.
+	ResponseFiles bool
+
+	// Passthrough describes the flags of the wrapped command, extending the
+	// passthroughWithArg list of [Sift]: a flag with a required argument
+	// forwards its value with it, and a flag with an optional argument
+	// forwards only an attached value, never the next argument.
+	Passthrough *PassthroughSpec
+
+	// StrictPassthrough when true makes [Sift] return an
+	// [*UnknownOptionError] for a flag that is neither known nor declared in
+	// passthroughWithArg or Passthrough, catching typos that would otherwise
+	// reach the wrapped command. Declare every flag of the wrapped command
+	// in Passthrough, including those without a value.
+	StrictPassthrough bool
+
+	// RequirePassthroughValues when true makes [Sift] return an error when
+	// fewer arguments are left than a passthrough flag takes values.
+	// By default the available arguments are forwarded.
+	RequirePassthroughValues bool
+
+	// KeepDelimiter when true makes [Sift] append the "--" delimiter to
+	// remaining, so that it can be forwarded to the wrapped command.
+	// Arguments after it are still returned as positional.
+	KeepDelimiter bool
+
+	// Delimiters lists the arguments that end flag parsing in place of
+	// "--", such as "-args" for wrappers of go test. Include "--" to keep
+	// it as a delimiter; when it is not, a "--" argument is positional.
+	// Later occurrences of a delimiter split the arguments after the
+	// first one into sections (see [Result.Sections]). If nil, the only
+	// delimiter is "--"; if empty but not nil, there is no delimiter, which
+	// cannot be combined with RequirePositionalDelimiter.
+	Delimiters []string
+
+	// TypeParsers maps field types to functions that parse flag values
+	// into fields of that type. Fields of types argsieve does not support
+	// itself are allowed when a parser is registered for them.
+	TypeParsers map[reflect.Type]ValueParser
+
+	// FieldParsers maps Go field paths (see [Flag.Field]) to functions that
+	// parse flag values into that field, taking precedence over
+	// TypeParsers. Every key must name a tagged field.
+	FieldParsers map[string]ValueParser
+
+	// FlagSet is an additional source of known flags, for flags defined with
+	// the flag package in code that does not use argsieve. Its flags are
+	// accepted as -name or --name, with the value syntax of the flag
+	// package, and are set with [flag.FlagSet.Set]. Flags declared by the
+	// options struct take precedence. The FlagSet is shared, not copied;
+	// parses using it must not run concurrently. Hook is not called for
+	// its flags.
+	FlagSet *flag.FlagSet
+
+	// Hook is called after each known flag is set. It can stop parsing
+	// early by returning [ErrStop] or abort it with another error.
+	Hook FlagHook
+
+	// FS is the file system response files and value files (see the
+	// fromfile tag) are read from. If nil, paths are opened on the
+	// operating system's file system.
+	FS fs.FS
+
+	// Stdin is read for value files named "-" (see the fromfile tag).
+	// If nil, [os.Stdin] is used.
+	Stdin io.Reader
 }
 
-// fieldInfo holds a reference to a struct field and whether it needs an argument.
+// fieldInfo binds a field's spec to the field value of a specific target.
 type fieldInfo struct {
-	field    reflect.Value
-	needsArg bool
-	isPtr    bool // true if field is a pointer to TextUnmarshaler
+	field reflect.Value
+	*fieldSpec
 }
 
 // sieve separates known flags from unknown flags and positional arguments.
 type sieve struct {
-	fields                     map[string]fieldInfo // flag name → field info
-	passthrough                map[string]struct{}
-	remaining                  []string
-	positional                 []string
-	strict                     bool
-	requirePositionalDelimiter bool
-	stopAtFirstPositional      bool
-	delimiterSeen              bool
+	schema        *schema
+	cfg           *Config
+	target        reflect.Value // struct value flags are bound to
+	passthrough   []string
+	remaining     []string
+	positional    []string
+	strict        bool
+	delimiterSeen bool
+	delimiterAt   int      // number of positionals before the delimiter
+	argv          []string // arguments being parsed, after response file expansion
+	lexer         Lexer
+	pos           int  // index in argv of the last argument read
+	argIndex      int  // index in argv of the argument being processed
+	recordOrder   bool // true if ordered should be filled in
+	lastKnown     int  // index in argv of the last argument marked known
+	ordered       []Arg
 }
 
 // Sift extracts known flags from args into target, returning unknown flags
@@ -65,6 +177,8 @@ type sieve struct {
 //
 // The passthroughWithArg parameter lists unknown flags that consume a value.
 // Without this hint, an unknown flag's value would be treated as positional.
+// Use [Config.Passthrough] to describe the flags of the wrapped command in
+// more detail.
 //
 // The cfg parameter allows optional configuration. Pass nil to use defaults.
 // When cfg.RequirePositionalDelimiter is true, positional arguments must
@@ -85,23 +199,7 @@ type sieve struct {
 // Panics if target is not a pointer to struct or if any tagged field
 // has an unsupported type.
 func Sift(target any, args []string, passthroughWithArg []string, cfg *Config) (remaining, positional []string, err error) {
-	s := &sieve{
-		fields:      make(map[string]fieldInfo),
-		passthrough: make(map[string]struct{}),
-	}
-
-	if cfg != nil {
-		s.requirePositionalDelimiter = cfg.RequirePositionalDelimiter
-		s.stopAtFirstPositional = cfg.StopAtFirstPositional
-	}
-
-	s.extractFields(target)
-
-	for _, p := range passthroughWithArg {
-		s.passthrough[p] = struct{}{}
-	}
-
-	return s.parse(args)
+	return NewParser(target, cfg).Sift(target, args, passthroughWithArg)
 }
 
 // Parse parses args into target in strict mode, returning only positional arguments.
@@ -128,101 +226,91 @@ func Sift(target any, args []string, passthroughWithArg []string, cfg *Config) (
 // Panics if target is not a pointer to struct or if any tagged field
 // has an unsupported type.
 func Parse(target any, args []string, cfg *Config) (positional []string, err error) {
-	s := &sieve{
-		fields:      make(map[string]fieldInfo),
-		passthrough: make(map[string]struct{}),
-		strict:      true,
-	}
-
-	if cfg != nil {
-		s.requirePositionalDelimiter = cfg.RequirePositionalDelimiter
-		s.stopAtFirstPositional = cfg.StopAtFirstPositional
-	}
-
-	s.extractFields(target)
+	return NewParser(target, cfg).Parse(target, args)
+}
 
-	_, positional, err = s.parse(args)
+// addRemaining appends a flag taken from the argument being processed to remaining.
+func (s *sieve) addRemaining(arg string) {
+	s.remaining = append(s.remaining, arg)
+	s.record(ArgPassthrough, arg, s.argIndex)
+}
 
-	return positional, err
+// addRemainingValue appends the value of a forwarded flag to remaining.
+func (s *sieve) addRemainingValue(value string) {
+	s.remaining = append(s.remaining, value)
+	s.record(ArgPassthroughValue, value, s.pos)
 }
 
-// Helper methods for cleaner append patterns.
-func (s *sieve) addRemaining(args ...string)  { s.remaining = append(s.remaining, args...) }
-func (s *sieve) addPositional(args ...string) { s.positional = append(s.positional, args...) }
+// addPositional appends the most recently read argument to positional.
+func (s *sieve) addPositional(arg string) {
+	s.positional = append(s.positional, arg)
+	s.record(ArgPositional, arg, s.pos)
+}
 
-// extractFields reads struct tags and stores field references.
-// Panics if target is not a pointer to a struct.
-func (s *sieve) extractFields(target any) {
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
-		panic(fmt.Sprintf("argsieve: target must be a pointer to struct, got %T", target))
+// markKnown records the argument at index as consumed by a known flag.
+// An argument holding several chained flags is recorded once.
+func (s *sieve) markKnown(index int) {
+	if index <= s.lastKnown {
+		return
 	}
 
-	s.extractFieldsFromValue(v.Elem())
+	s.lastKnown = index
+	s.record(ArgKnown, s.argv[index], index)
 }
 
-// extractFieldsFromValue recursively extracts fields from a struct value,
-// including fields from embedded structs.
-func (s *sieve) extractFieldsFromValue(v reflect.Value) {
-	t := v.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		fieldType := t.Field(i)
-		fieldValue := v.Field(i)
-
-		// Recursively process embedded structs
-		if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
-			s.extractFieldsFromValue(fieldValue)
-			continue
-		}
+// record appends to the ordered argument list when it was requested.
+func (s *sieve) record(kind ArgKind, value string, index int) {
+	if s.recordOrder {
+		s.ordered = append(s.ordered, Arg{Value: value, Kind: kind, Index: index})
+	}
+}
 
-		short := fieldType.Tag.Get("short")
-		long := fieldType.Tag.Get("long")
+// next reads the next argument verbatim.
+func (s *sieve) next() (string, bool) {
+	tok, ok := s.lexer.NextArg()
+	if !ok {
+		return "", false
+	}
 
-		// Skip fields without tags
-		if short == "" && long == "" {
-			continue
-		}
+	s.pos = tok.Index
 
-		// Determine field type and whether it needs an argument
-		kind := fieldType.Type.Kind()
-		var info fieldInfo
+	return tok.Arg, true
+}
 
-		switch {
-		case kind == reflect.Bool:
-			info = fieldInfo{field: fieldValue, needsArg: false}
-		case kind == reflect.String:
-			info = fieldInfo{field: fieldValue, needsArg: true}
-		case kind == reflect.Ptr:
-			// Pointer to TextUnmarshaler - nil when flag absent, allocated when present
-			elemType := fieldType.Type.Elem()
-			if reflect.PointerTo(elemType).Implements(textUnmarshalerType) {
-				info = fieldInfo{field: fieldValue, needsArg: true, isPtr: true}
-			} else {
-				panic(fmt.Sprintf("argsieve: pointer field %s must point to type implementing encoding.TextUnmarshaler",
-					fieldType.Name))
-			}
-		case fieldValue.CanAddr() && reflect.PointerTo(fieldType.Type).Implements(textUnmarshalerType):
-			// Field's pointer type implements encoding.TextUnmarshaler
-			info = fieldInfo{field: fieldValue, needsArg: true}
-		default:
-			panic(fmt.Sprintf("argsieve: field %s has unsupported type %s (must be string, bool, or implement encoding.TextUnmarshaler)",
-				fieldType.Name, fieldType.Type))
-		}
+// lookup returns the field bound to a prefixed flag name (-x, --name).
+func (s *sieve) lookup(flag string) (fieldInfo, bool) {
+	spec, known := s.schema.flags[flag]
+	if !known {
+		return fieldInfo{}, false
+	}
 
-		if short != "" {
-			s.fields[short] = info
-		}
+	return s.bind(spec), true
+}
 
-		if long != "" {
-			s.fields[long] = info
-		}
-	}
+// bind returns the target's field described by spec.
+func (s *sieve) bind(spec *fieldSpec) fieldInfo {
+	return fieldInfo{field: s.target.FieldByIndex(spec.index), fieldSpec: spec}
 }
 
 // setField assigns a value to a field based on its type.
 // Returns an error if TextUnmarshaler.UnmarshalText fails.
 func (s *sieve) setField(info fieldInfo, value string) error {
+	if info.fromFile && info.needsArg {
+		var err error
+		if value, err = s.readValueFile(value); err != nil {
+			return err
+		}
+	}
+
+	// Registered parsers take precedence over the type's own parsing
+	if parse, ok := s.cfg.valueParser(info.fieldSpec); ok {
+		return setParsed(info, parse, value)
+	}
+
+	if info.value {
+		return setFlagValue(info, value)
+	}
+
 	// Handle pointer fields - allocate and set
 	if info.isPtr {
 		elemType := info.field.Type().Elem()
@@ -244,98 +332,317 @@ func (s *sieve) setField(info fieldInfo, value string) error {
 	}
 
 	// Fall back to built-in types
-	if info.needsArg {
-		info.field.SetString(value)
-	} else {
+	switch kind := info.field.Kind(); {
+	case kind == reflect.Bool:
 		info.field.SetBool(true)
+	case isIntKind(kind) && info.field.CanInt():
+		n, err := strconv.ParseInt(value, 10, info.field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		info.field.SetInt(n)
+	case isIntKind(kind):
+		n, err := strconv.ParseUint(value, 10, info.field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		info.field.SetUint(n)
+	default:
+		info.field.SetString(value)
 	}
 
 	return nil
 }
 
-// handleLong processes --name or --name=value arguments.
-func (s *sieve) handleLong(arg string, next func() (string, bool)) error {
-	name, eqValue, hasEquals := strings.Cut(arg[2:], "=")
+// readValueFile resolves a value of the form @path or file:path to the
+// contents of the file at path with surrounding whitespace trimmed.
+// The path "-" reads standard input. Other values are returned unchanged.
+func (s *sieve) readValueFile(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		if name, ok = strings.CutPrefix(value, "file:"); !ok {
+			return value, nil
+		}
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if name == "-" {
+		data, err = io.ReadAll(s.cfg.Stdin)
+	} else {
+		data, err = fs.ReadFile(s.cfg.FS, name)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// isIntKind reports whether kind is a signed or unsigned integer kind.
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNumericShorthand reports whether arg has the form -<digits>.
+func isNumericShorthand(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+
+	for _, c := range arg[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNegativeNumber reports whether arg is a negative decimal number
+// such as -5 or -0.25.
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' || (arg[1] != '.' && (arg[1] < '0' || arg[1] > '9')) {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(arg, 64)
 
-	info, known := s.fields[name]
+	return err == nil
+}
+
+// handleLong processes --name or --name=value arguments.
+func (s *sieve) handleLong(tok Token, next func() (string, bool)) error {
+	attached, hasEquals := s.lexer.attached()
+	flag := "--" + tok.Name
 
-	// Unknown flag - reject in strict mode or check passthrough list
+	info, known := s.lookup(flag)
 	if !known {
-		if s.strict {
-			return fmt.Errorf("%w: unknown option --%s", ErrParse, name)
+		if handled, err := s.bindFlagSet(tok.Name, flag, attached.Value, hasEquals, next); handled {
+			return err
 		}
 
-		_, isPassthrough := s.passthrough["--"+name]
+		return s.passLong(tok.Arg, flag, hasEquals, next)
+	}
+
+	return s.bindLong(info, flag, attached.Value, hasEquals, next)
+}
 
-		if isPassthrough && !hasEquals {
-			if value, ok := next(); ok {
-				s.addRemaining(arg, value)
+// handleSingleDashLong processes -name or -name=value arguments when
+// [Config.SingleDashLong] is enabled. Returns false if arg should be
+// parsed as a chain of short flags instead.
+func (s *sieve) handleSingleDashLong(arg string, next func() (string, bool)) (bool, error) {
+	name, eqValue, hasEquals := strings.Cut(arg[1:], "=")
+
+	if info, known := s.lookup("--" + name); known {
+		return true, s.bindLong(info, "-"+name, eqValue, hasEquals, next)
+	}
+
+	if _, declared := s.passthroughArg("-" + name); declared {
+		return true, s.passLong(arg, "-"+name, hasEquals, next)
+	}
+
+	// -name=value is a chain only if a short flag in it takes the value;
+	// otherwise it is an unknown long flag and is forwarded verbatim.
+	if hasEquals {
+		if s.chainTakesValue(arg[1:]) {
+			return false, nil
+		}
+
+		return true, s.passLong(arg, "-"+name, hasEquals, next)
+	}
+
+	if _, _, known := s.matchShort(arg[1:]); known {
+		return false, nil
+	}
 
-				return nil
+	// A declared passthrough short flag is also parsed as a chain.
+	_, size := utf8.DecodeRuneInString(name)
+	if _, declared := s.passthroughArg("-" + name[:size]); declared {
+		return false, nil
+	}
+
+	return true, s.passLong(arg, "-"+name, hasEquals, next)
+}
+
+// chainTakesValue reports whether flags is a chain of known or declared
+// passthrough short flags ending in one that takes a value before "=".
+func (s *sieve) chainTakesValue(flags string) bool {
+	for len(flags) > 0 && flags[0] != '=' {
+		flag, info, known := s.matchShort(flags)
+		if known {
+			if info.needsArg || (info.value && strings.HasPrefix(flags[len(flag):], "=")) {
+				return true
+			}
+		} else {
+			pass, declared := s.passthroughArg("-" + flag)
+			if !declared {
+				return false
+			}
+
+			if pass.hasArg != NoArgument {
+				return true
 			}
 		}
 
-		s.addRemaining(arg)
+		flags = flags[len(flag):]
+	}
+
+	return false
+}
+
+// passLong handles an unknown long flag: rejects it in strict mode,
+// otherwise forwards it to remaining along with its values if listed
+// in the passthrough list.
+func (s *sieve) passLong(arg, flag string, hasEquals bool, next func() (string, bool)) error {
+	pass, declared := s.passthroughArg(flag)
+	if err := s.unknownOption(flag, declared); err != nil {
+		return err
+	}
 
+	s.addRemaining(arg)
+
+	if pass.hasArg != RequiredArgument {
 		return nil
 	}
 
-	// Known bool flag
+	if hasEquals {
+		return s.passValues(flag, pass.arity-1, next)
+	}
+
+	return s.passValues(flag, pass.arity, next)
+}
+
+// bindLong sets a known long flag from its attached value or the next arg.
+func (s *sieve) bindLong(info fieldInfo, flag, eqValue string, hasEquals bool, next func() (string, bool)) error {
+	// Known bool flag; a boolean flag.Value also accepts --name=false
 	if !info.needsArg {
-		return s.setField(info, "")
+		if info.value && hasEquals {
+			return s.bindValue(info, flag, eqValue)
+		}
+
+		return s.bindValue(info, flag, "")
 	}
 
 	// Known string flag with equals
 	if hasEquals {
-		if err := s.setField(info, eqValue); err != nil {
-			return fmt.Errorf("%w: invalid value for --%s: %v", ErrParse, name, err)
-		}
-
-		return nil
+		return s.bindValue(info, flag, eqValue)
 	}
 
 	// Known string flag - needs argument from next arg
 	value, ok := next()
 	if !ok {
-		return fmt.Errorf("%w: missing value for --%s", ErrParse, name)
+		return fmt.Errorf("%w: missing value for %s", ErrParse, flag)
 	}
 
+	return s.bindValue(info, flag, value)
+}
+
+// bindValue sets a known flag's field from its raw value. In Sift mode a
+// field with a forward tag is also appended to remaining under its
+// translated name. Returns the error of [Config.Hook], if any.
+func (s *sieve) bindValue(info fieldInfo, flag, value string) error {
 	if err := s.setField(info, value); err != nil {
-		return fmt.Errorf("%w: invalid value for --%s: %v", ErrParse, name, err)
+		return fmt.Errorf("%w: invalid value for %s: %v", ErrParse, flag, err)
+	}
+
+	s.markKnown(s.argIndex)
+	s.markKnown(s.pos)
+
+	if info.forward != "" && !s.strict {
+		switch {
+		case info.needsArg:
+			s.addRemaining(info.forward)
+			s.addRemainingValue(value)
+		case value != "":
+			// Boolean flag.Value given a value, as in --name=false
+			s.addRemaining(info.forward + "=" + value)
+		default:
+			s.addRemaining(info.forward)
+		}
+	}
+
+	if s.cfg.Hook != nil {
+		return s.cfg.Hook.HandleFlag(info.flag(), value)
 	}
 
 	return nil
 }
 
+// matchShort returns the short flag name at the start of flags. With
+// [Config.MultiCharShort] the longest declared short name wins; otherwise,
+// or if none matches, the name is the first rune.
+func (s *sieve) matchShort(flags string) (string, fieldInfo, bool) {
+	if s.cfg.MultiCharShort {
+		for n := min(len(flags), s.schema.maxShortLen); n > 1; n-- {
+			if info, known := s.lookup("-" + flags[:n]); known {
+				return flags[:n], info, true
+			}
+		}
+	}
+
+	_, size := utf8.DecodeRuneInString(flags)
+	flag := flags[:size]
+	info, known := s.lookup("-" + flag)
+
+	return flag, info, known
+}
+
 // handleShort processes -x, -xvalue, or -xyz combined arguments.
 func (s *sieve) handleShort(arg string, next func() (string, bool)) error {
-	flags := arg[1:]
+	if s.cfg.FlagSet != nil {
+		if handled, err := s.handleFlagSetShort(arg, next); handled {
+			return err
+		}
+	}
 
-	for j := 0; j < len(flags); j++ {
-		flag := string(flags[j])
-		tail := flags[j+1:]
+	if s.cfg.SingleDashLong {
+		if handled, err := s.handleSingleDashLong(arg, next); handled {
+			return err
+		}
+	}
 
-		info, known := s.fields[flag]
+	for flags := arg[1:]; len(flags) > 0; {
+		flag, info, known := s.matchShort(flags)
+		tail := flags[len(flag):]
+		flags = tail
 
 		// Unknown flag - check passthrough list or pass through as boolean
 		if !known {
-			if s.strict {
-				return fmt.Errorf("%w: unknown option -%s", ErrParse, flag)
-			}
-
 			prefixedFlag := "-" + flag
 
-			if _, ok := s.passthrough[prefixedFlag]; ok {
+			pass, declared := s.passthroughArg(prefixedFlag)
+			if err := s.unknownOption(prefixedFlag, declared); err != nil {
+				return err
+			}
+
+			switch pass.hasArg {
+			case RequiredArgument:
 				if len(tail) > 0 {
-					s.addRemaining("-" + flag + tail)
+					s.addRemaining(prefixedFlag + tail)
 
-					return nil // tail consumed as passthrough value
+					// tail consumed as the first passthrough value
+					return s.passValues(prefixedFlag, pass.arity-1, next)
 				}
 
-				if value, ok := next(); ok {
-					s.addRemaining(prefixedFlag, value)
+				s.addRemaining(prefixedFlag)
+
+				return s.passValues(prefixedFlag, pass.arity, next)
+			case OptionalArgument:
+				if len(tail) > 0 {
+					s.addRemaining(prefixedFlag + tail)
 
-					continue
+					return nil // tail consumed as passthrough value
 				}
 			}
 
@@ -344,9 +651,13 @@ func (s *sieve) handleShort(arg string, next func() (string, bool)) error {
 			continue
 		}
 
-		// Known bool flag
+		// Known bool flag; a boolean flag.Value also accepts -x=false
 		if !info.needsArg {
-			if err := s.setField(info, ""); err != nil {
+			if value, ok := strings.CutPrefix(tail, "="); ok && info.value {
+				return s.bindValue(info, "-"+flag, value)
+			}
+
+			if err := s.bindValue(info, "-"+flag, ""); err != nil {
 				return err
 			}
 
@@ -355,11 +666,7 @@ func (s *sieve) handleShort(arg string, next func() (string, bool)) error {
 
 		// Known string flag - value attached
 		if len(tail) > 0 {
-			if err := s.setField(info, tail); err != nil {
-				return fmt.Errorf("%w: invalid value for -%s: %v", ErrParse, flag, err)
-			}
-
-			return nil
+			return s.bindValue(info, "-"+flag, tail)
 		}
 
 		// Known string flag - value in next arg
@@ -368,11 +675,7 @@ func (s *sieve) handleShort(arg string, next func() (string, bool)) error {
 			return fmt.Errorf("%w: missing value for -%s", ErrParse, flag)
 		}
 
-		if err := s.setField(info, value); err != nil {
-			return fmt.Errorf("%w: invalid value for -%s: %v", ErrParse, flag, err)
-		}
-
-		return nil
+		return s.bindValue(info, "-"+flag, value)
 	}
 
 	return nil
@@ -381,41 +684,85 @@ func (s *sieve) handleShort(arg string, next func() (string, bool)) error {
 // parse separates args into known flags (bound to target), unknown flags, and positionals.
 // Arguments after "--" are treated as positional (the "--" itself is not included).
 func (s *sieve) parse(args []string) (remaining, positional []string, err error) {
-	next, stop := iter.Pull(slices.Values(args))
-	defer stop()
+	if s.cfg.ResponseFiles {
+		if args, err = s.expandResponseFiles(args, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	s.argv = args
+	s.lexer = Lexer{args: args, delimiters: s.cfg.Delimiters}
+	s.lastKnown = -1
+	next := s.next
+
+	for tok, ok := s.lexer.Next(); ok; tok, ok = s.lexer.Next() {
+		s.argIndex, s.pos = tok.Index, tok.Index
+		arg := tok.Arg
 
-	for arg, ok := next(); ok; arg, ok = next() {
 		switch {
-		case arg == "--":
+		case tok.Kind == TokenDelimiter:
 			s.delimiterSeen = true
-			// Drain remaining args as positional (don't pass "--" through)
-			for arg, ok := next(); ok; arg, ok = next() {
-				s.addPositional(arg)
-			}
+			s.delimiterAt = len(s.positional)
+			s.record(ArgDelimiter, arg, s.pos)
 
-		case strings.HasPrefix(arg, "--"):
-			if err := s.handleLong(arg, next); err != nil {
-				return nil, nil, err
+			if s.cfg.KeepDelimiter && !s.strict {
+				s.remaining = append(s.remaining, arg)
 			}
 
-		case strings.HasPrefix(arg, "-") && len(arg) > 1:
-			if err := s.handleShort(arg, next); err != nil {
-				return nil, nil, err
-			}
+			// Don't pass the delimiter through
+			s.drainPositional()
+
+		case tok.Kind == TokenLong:
+			err = s.handleLong(tok, next)
+
+		case s.schema.numeric != nil && isNumericShorthand(arg):
+			err = s.bindValue(s.bind(s.schema.numeric), arg, arg[1:])
+
+		case tok.Kind == TokenShort && !(s.cfg.NegativeNumbersPositional && isNegativeNumber(arg)):
+			err = s.handleShort(arg, next)
 
 		default:
-			if s.requirePositionalDelimiter && !s.delimiterSeen {
-				return nil, nil, fmt.Errorf("%w: positional argument %q not allowed before \"--\" delimiter", ErrParse, arg)
-			}
-			s.addPositional(arg)
-			if s.stopAtFirstPositional {
-				// Drain remaining args as positional
-				for arg, ok := next(); ok; arg, ok = next() {
-					s.addPositional(arg)
-				}
-			}
+			err = s.handlePositional(arg)
+		}
+
+		if errors.Is(err, ErrStop) {
+			s.drainPositional()
+
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
 		}
 	}
 
 	return s.remaining, s.positional, nil
 }
+
+// handlePositional processes a positional argument, draining the rest of
+// args as positional when [Config.StopAtFirstPositional] is set.
+func (s *sieve) handlePositional(arg string) error {
+	if s.cfg.RequirePositionalDelimiter && !s.delimiterSeen {
+		delimiter := "--"
+		if len(s.cfg.Delimiters) > 0 {
+			delimiter = s.cfg.Delimiters[0]
+		}
+
+		return fmt.Errorf("%w: positional argument %q not allowed before %q delimiter", ErrParse, arg, delimiter)
+	}
+
+	s.addPositional(arg)
+
+	if s.cfg.StopAtFirstPositional {
+		s.drainPositional()
+	}
+
+	return nil
+}
+
+// drainPositional adds the remaining args to positional verbatim.
+func (s *sieve) drainPositional() {
+	for arg, ok := s.next(); ok; arg, ok = s.next() {
+		s.addPositional(arg)
+	}
+}
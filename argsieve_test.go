@@ -2,7 +2,9 @@ package argsieve
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -939,3 +941,608 @@ func TestSift_StopAtFirstPositional(t *testing.T) {
 		})
 	}
 }
+
+func TestSift_SingleDashLong(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args               []string
+		passthroughWithArg []string
+		wantRemaining      []string
+		wantPositional     []string
+		wantRegion         string
+		wantVerbose        bool
+		wantDebug          bool
+		wantErr            bool
+	}{
+		"long name with separate value": {
+			args:       []string{"-region", "us-west-2"},
+			wantRegion: "us-west-2",
+		},
+		"long name with equals value": {
+			args:       []string{"-region=us-west-2"},
+			wantRegion: "us-west-2",
+		},
+		"long bool flag": {
+			args:        []string{"-verbose"},
+			wantVerbose: true,
+		},
+		"double dash long still works": {
+			args:       []string{"--region", "us-west-2"},
+			wantRegion: "us-west-2",
+		},
+		"falls back to chained shorts": {
+			args:        []string{"-vd"},
+			wantVerbose: true,
+			wantDebug:   true,
+		},
+		"falls back to short with attached value": {
+			args:       []string{"-rus-west-2"},
+			wantRegion: "us-west-2",
+		},
+		"unknown passed through verbatim": {
+			args:           []string{"-json", "foo"},
+			wantRemaining:  []string{"-json"},
+			wantPositional: []string{"foo"},
+		},
+		"unknown with equals passed through verbatim": {
+			args:          []string{"-target=foo=bar"},
+			wantRemaining: []string{"-target=foo=bar"},
+		},
+		"unknown passthrough with value": {
+			args:               []string{"-target", "foo=bar", "plan"},
+			passthroughWithArg: []string{"-target"},
+			wantRemaining:      []string{"-target", "foo=bar"},
+			wantPositional:     []string{"plan"},
+		},
+		"passthrough starting with known short": {
+			args:               []string{"-chdir=dir", "-var", "x=1"},
+			passthroughWithArg: []string{"-var"},
+			wantRemaining:      []string{"-chdir=dir", "-var", "x=1"},
+		},
+		"unknown with equals starting with known short": {
+			args:          []string{"-var=1"},
+			wantRemaining: []string{"-var=1"},
+		},
+		"chained shorts with attached equals value": {
+			args:        []string{"-vrus=west"},
+			wantRegion:  "us=west",
+			wantVerbose: true,
+		},
+		"missing value": {
+			args:    []string{"-region"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags testFlags
+			cfg := &Config{SingleDashLong: true}
+			remaining, positional, err := Sift(&flags, tc.args, tc.passthroughWithArg, cfg)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRemaining, remaining, "remaining")
+			assert.Equal(t, tc.wantPositional, positional, "positional")
+			assert.Equal(t, tc.wantRegion, flags.Region, "region")
+			assert.Equal(t, tc.wantVerbose, flags.Verbose, "verbose")
+			assert.Equal(t, tc.wantDebug, flags.Debug, "debug")
+		})
+	}
+}
+
+func TestParse_SingleDashLong(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args        []string
+		wantRegion  string
+		wantVerbose bool
+		wantErr     bool
+		errContains string
+	}{
+		"long name": {
+			args:        []string{"-verbose", "-region=us-west-2"},
+			wantVerbose: true,
+			wantRegion:  "us-west-2",
+		},
+		"unknown long name rejected": {
+			args:        []string{"-unknown=1"},
+			wantErr:     true,
+			errContains: "unknown option -unknown",
+		},
+		"unknown char in short chain rejected": {
+			args:        []string{"-vx"},
+			wantErr:     true,
+			errContains: "unknown option -x",
+		},
+		"invalid value reports single dash": {
+			args:        []string{"-region"},
+			wantErr:     true,
+			errContains: "missing value for -region",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags testFlags
+			_, err := Parse(&flags, tc.args, &Config{SingleDashLong: true})
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRegion, flags.Region)
+			assert.Equal(t, tc.wantVerbose, flags.Verbose)
+		})
+	}
+}
+
+func TestSift_MultiCharShort(t *testing.T) {
+	t.Parallel()
+
+	type multiFlags struct {
+		NoCheck bool   `short:"nc"`
+		TTY     bool   `short:"tt"`
+		Name    string `short:"nm"`
+		Verbose bool   `short:"v"`
+		Number  bool   `short:"n"`
+	}
+
+	tests := map[string]struct {
+		args          []string
+		wantRemaining []string
+		want          multiFlags
+		wantErr       bool
+	}{
+		"multi-char flag": {
+			args: []string{"-nc"},
+			want: multiFlags{NoCheck: true},
+		},
+		"single char prefix still matches": {
+			args: []string{"-n"},
+			want: multiFlags{Number: true},
+		},
+		"longest match then chain": {
+			args: []string{"-ncvtt"},
+			want: multiFlags{NoCheck: true, Verbose: true, TTY: true},
+		},
+		"falls back to single char": {
+			args: []string{"-nv"},
+			want: multiFlags{Number: true, Verbose: true},
+		},
+		"multi-char flag with attached value": {
+			args: []string{"-nmfoo"},
+			want: multiFlags{Name: "foo"},
+		},
+		"multi-char flag with separate value": {
+			args: []string{"-nm", "foo"},
+			want: multiFlags{Name: "foo"},
+		},
+		"unknown chars passed through singly": {
+			args:          []string{"-xnc"},
+			wantRemaining: []string{"-x"},
+			want:          multiFlags{NoCheck: true},
+		},
+		"missing value": {
+			args:    []string{"-vnm"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags multiFlags
+			remaining, _, err := Sift(&flags, tc.args, nil, &Config{MultiCharShort: true})
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRemaining, remaining, "remaining")
+			assert.Equal(t, tc.want, flags)
+		})
+	}
+}
+
+func TestSift_UnicodeShortFlags(t *testing.T) {
+	t.Parallel()
+
+	type unicodeFlags struct {
+		Accent  bool   `short:"é"`
+		Name    string `short:"ñ"`
+		Verbose bool   `short:"v"`
+	}
+
+	tests := map[string]struct {
+		args          []string
+		wantRemaining []string
+		want          unicodeFlags
+	}{
+		"non-ASCII bool flag": {
+			args: []string{"-é"},
+			want: unicodeFlags{Accent: true},
+		},
+		"non-ASCII chain": {
+			args: []string{"-véñ", "x"},
+			want: unicodeFlags{Accent: true, Verbose: true, Name: "x"},
+		},
+		"attached UTF-8 value": {
+			args: []string{"-ñжёлтый"},
+			want: unicodeFlags{Name: "жёлтый"},
+		},
+		"chain with attached UTF-8 value": {
+			args: []string{"-éñü"},
+			want: unicodeFlags{Accent: true, Name: "ü"},
+		},
+		"unknown non-ASCII flag kept whole": {
+			args:          []string{"-üv"},
+			wantRemaining: []string{"-ü"},
+			want:          unicodeFlags{Verbose: true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags unicodeFlags
+			remaining, _, err := Sift(&flags, tc.args, nil, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRemaining, remaining, "remaining")
+			assert.Equal(t, tc.want, flags)
+		})
+	}
+}
+
+func TestSift_PanicsOnInvalidShortTag(t *testing.T) {
+	t.Parallel()
+
+	type multiChar struct {
+		NoCheck bool `short:"nc"`
+	}
+
+	type invalidUTF8 struct {
+		Bad bool `short:"\xff"`
+	}
+
+	assert.Panics(t, func() {
+		var flags multiChar
+		_, _, _ = Sift(&flags, []string{}, nil, nil)
+	})
+
+	assert.Panics(t, func() {
+		var flags invalidUTF8
+		_, _, _ = Sift(&flags, []string{}, nil, &Config{MultiCharShort: true})
+	})
+
+	assert.NotPanics(t, func() {
+		var flags multiChar
+		_, _, _ = Sift(&flags, []string{}, nil, &Config{MultiCharShort: true})
+	})
+}
+
+func TestParse_NumericShorthand(t *testing.T) {
+	t.Parallel()
+
+	type numericFlags struct {
+		Lines   int  `short:"n" long:"lines" numeric:"true"`
+		Verbose bool `short:"v"`
+	}
+
+	tests := map[string]struct {
+		args           []string
+		cfg            *Config
+		wantLines      int
+		wantVerbose    bool
+		wantPositional []string
+		wantErr        bool
+	}{
+		"digits shorthand": {
+			args:           []string{"-20", "file"},
+			wantLines:      20,
+			wantPositional: []string{"file"},
+		},
+		"zero shorthand": {
+			args: []string{"-0"},
+		},
+		"short flag with value": {
+			args:      []string{"-n", "5"},
+			wantLines: 5,
+		},
+		"long flag with equals": {
+			args:      []string{"--lines=7"},
+			wantLines: 7,
+		},
+		"shorthand among flags": {
+			args:        []string{"-v", "-3"},
+			wantLines:   3,
+			wantVerbose: true,
+		},
+		"shorthand wins over negative numbers": {
+			args:           []string{"-9", "-1.5"},
+			cfg:            &Config{NegativeNumbersPositional: true},
+			wantLines:      9,
+			wantPositional: []string{"-1.5"},
+		},
+		"invalid value": {
+			args:    []string{"-n", "many"},
+			wantErr: true,
+		},
+		"out of range": {
+			args:    []string{"-99999999999999999999"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags numericFlags
+			positional, err := Parse(&flags, tc.args, tc.cfg)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPositional, positional)
+			assert.Equal(t, tc.wantLines, flags.Lines)
+			assert.Equal(t, tc.wantVerbose, flags.Verbose)
+		})
+	}
+}
+
+func TestParse_NegativeNumbersPositional(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args           []string
+		wantPositional []string
+		wantVerbose    bool
+		wantErr        bool
+	}{
+		"negative integer": {
+			args:           []string{"-v", "-5"},
+			wantVerbose:    true,
+			wantPositional: []string{"-5"},
+		},
+		"negative decimal": {
+			args:           []string{"-0.25", "-.5"},
+			wantPositional: []string{"-0.25", "-.5"},
+		},
+		"value of known flag": {
+			args:           []string{"-r", "-5", "-7"},
+			wantPositional: []string{"-7"},
+		},
+		"non-numeric still a flag": {
+			args:    []string{"-inf"},
+			wantErr: true,
+		},
+		"digit-led cluster still a flag": {
+			args:    []string{"-5x"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags testFlags
+			positional, err := Parse(&flags, tc.args, &Config{NegativeNumbersPositional: true})
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPositional, positional)
+			assert.Equal(t, tc.wantVerbose, flags.Verbose)
+		})
+	}
+}
+
+func TestSift_PanicsOnInvalidNumericField(t *testing.T) {
+	t.Parallel()
+
+	type notInteger struct {
+		Lines string `numeric:"true"`
+	}
+
+	type twoNumeric struct {
+		Lines  int  `numeric:"true"`
+		Signal uint `numeric:"true"`
+	}
+
+	assert.Panics(t, func() {
+		var flags notInteger
+		_, _, _ = Sift(&flags, []string{}, nil, nil)
+	})
+
+	assert.Panics(t, func() {
+		var flags twoNumeric
+		_, _, _ = Sift(&flags, []string{}, nil, nil)
+	})
+}
+
+func TestSift_ValueFromFile(t *testing.T) {
+	t.Parallel()
+
+	type secretFlags struct {
+		Token   string    `long:"token" fromfile:"true"`
+		Level   *logLevel `short:"l" fromfile:"true"`
+		Profile string    `short:"p"`
+	}
+
+	fsys := fstest.MapFS{
+		"token.txt": {Data: []byte("  s3cr3t\n")},
+		"level.txt": {Data: []byte("debug\n")},
+	}
+
+	tests := map[string]struct {
+		args        []string
+		stdin       string
+		wantToken   string
+		wantLevel   *logLevel
+		wantProfile string
+		wantErr     bool
+	}{
+		"at path": {
+			args:      []string{"--token", "@token.txt"},
+			wantToken: "s3cr3t",
+		},
+		"file prefix": {
+			args:      []string{"--token=file:token.txt"},
+			wantToken: "s3cr3t",
+		},
+		"stdin": {
+			args:      []string{"--token", "@-"},
+			stdin:     "from-stdin\n",
+			wantToken: "from-stdin",
+		},
+		"file prefix stdin": {
+			args:      []string{"--token", "file:-"},
+			stdin:     "from-stdin",
+			wantToken: "from-stdin",
+		},
+		"plain value unchanged": {
+			args:      []string{"--token", "literal"},
+			wantToken: "literal",
+		},
+		"text unmarshaler from file": {
+			args:      []string{"-l@level.txt"},
+			wantLevel: ptrTo(logLevelDebug),
+		},
+		"untagged field not read": {
+			args:        []string{"-p", "@token.txt"},
+			wantProfile: "@token.txt",
+		},
+		"missing file": {
+			args:    []string{"--token", "@missing.txt"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags secretFlags
+			cfg := &Config{FS: fsys, Stdin: strings.NewReader(tc.stdin)}
+			_, _, err := Sift(&flags, tc.args, nil, cfg)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantToken, flags.Token)
+			assert.Equal(t, tc.wantLevel, flags.Level)
+			assert.Equal(t, tc.wantProfile, flags.Profile)
+		})
+	}
+}
+
+func TestSift_Forward(t *testing.T) {
+	t.Parallel()
+
+	type forwardFlags struct {
+		Profile string `long:"profile" forward:"-p"`
+		Verbose bool   `short:"v" forward:"--verbose"`
+		Region  string `short:"r"`
+		Lines   int    `numeric:"true" forward:"-n"`
+	}
+
+	tests := map[string]struct {
+		args          []string
+		wantRemaining []string
+		wantProfile   string
+		wantVerbose   bool
+	}{
+		"value flag translated": {
+			args:          []string{"--profile", "dev"},
+			wantRemaining: []string{"-p", "dev"},
+			wantProfile:   "dev",
+		},
+		"equals value translated": {
+			args:          []string{"--profile=dev"},
+			wantRemaining: []string{"-p", "dev"},
+			wantProfile:   "dev",
+		},
+		"bool flag translated": {
+			args:          []string{"-v"},
+			wantRemaining: []string{"--verbose"},
+			wantVerbose:   true,
+		},
+		"relative order preserved": {
+			args:          []string{"-x", "--profile", "dev", "-y", "-vr", "eu", "-z"},
+			wantRemaining: []string{"-x", "-p", "dev", "-y", "--verbose", "-z"},
+			wantProfile:   "dev",
+			wantVerbose:   true,
+		},
+		"numeric shorthand translated": {
+			args:          []string{"-20"},
+			wantRemaining: []string{"-n", "20"},
+		},
+		"untagged flag consumed": {
+			args: []string{"-r", "eu"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags forwardFlags
+			remaining, _, err := Sift(&flags, tc.args, nil, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRemaining, remaining, "remaining")
+			assert.Equal(t, tc.wantProfile, flags.Profile, "profile")
+			assert.Equal(t, tc.wantVerbose, flags.Verbose, "verbose")
+		})
+	}
+}
+
+func TestParse_ForwardIgnored(t *testing.T) {
+	t.Parallel()
+
+	type forwardFlags struct {
+		Profile string `long:"profile" forward:"-p"`
+	}
+
+	var flags forwardFlags
+	positional, err := Parse(&flags, []string{"--profile", "dev", "host"}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"host"}, positional)
+	assert.Equal(t, "dev", flags.Profile)
+}
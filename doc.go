@@ -12,6 +12,24 @@
 // [Parse] is strict mode that errors on any unknown flag - use this when
 // building standalone CLI tools.
 //
+// # Typed Entry Points
+//
+// [ParseAs] and [SiftAs] construct and return the options value, and report
+// an invalid options struct as an error wrapping [ErrSchema] rather than
+// panicking:
+//
+//	opts, positional, err := argsieve.ParseAs[Options](os.Args[1:], nil)
+//
+// # Reusable Parsers
+//
+// [Sift] and [Parse] inspect the options struct type once and cache the
+// result. To also reuse the configuration across many calls, create a
+// [Parser] with [NewParser]:
+//
+//	p := argsieve.NewParser((*Options)(nil), cfg)
+//	var opts Options
+//	positional, err := p.Parse(&opts, args)
+//
 // # Configuration
 //
 // Both [Sift] and [Parse] accept an optional [Config] parameter. Pass nil
@@ -32,6 +50,62 @@
 //	positional, err := argsieve.Parse(&opts, args, cfg)
 //	// "-v file -d" → -v parsed, ["file", "-d"] are positional
 //
+// Use [Config.SingleDashLong] to accept long flags with a single dash,
+// as in the standard flag package (-name, -name=value):
+//
+//	cfg := &argsieve.Config{SingleDashLong: true}
+//	remaining, positional, err := argsieve.Sift(&opts, args, []string{"-var"}, cfg)
+//	// "-chdir=dir -var x=1" → --chdir bound, ["-var", "x=1"] forwarded
+//
+// Use [Config.MultiCharShort] to allow short tags longer than one
+// character; the longest declared short name is matched first:
+//
+//	type Options struct {
+//	    NoCheck bool `short:"nc"`
+//	    Verbose bool `short:"v"`
+//	}
+//	// "-ncv" → -nc and -v set
+//
+// Use [Config.ResponseFiles] to expand @path arguments into the contents
+// of the file at path before parsing, as compilers do for argument lists
+// that exceed the OS limit. Files are read from [Config.FS], which
+// defaults to the operating system's file system:
+//
+//	cfg := &argsieve.Config{ResponseFiles: true}
+//	positional, err := argsieve.Parse(&opts, args, cfg)
+//	// "@build.rsp main.c" → contents of build.rsp, then "main.c"
+//
+// # Describing Wrapped Tools
+//
+// Instead of listing value-taking flags in passthroughWithArg, describe the
+// wrapped command's options in a [PassthroughSpec], built from a getopt(3)
+// optstring or a getopt_long(3) option table, and set it in
+// [Config.Passthrough]:
+//
+//	var spec argsieve.PassthroughSpec
+//	err := spec.AddOptstring("46AaCfGgKkMNnqsTtVvXxYyB:b:c:D:E:e:F:I:i:J:L:l:m:O:o:p:Q:R:S:W:w:")
+//	cfg := &argsieve.Config{Passthrough: &spec}
+//	// "-p 2222 -tt host" → remaining ["-p", "2222", "-t", "-t"], positional ["host"]
+//
+// Flags with an optional argument ("x::" in an optstring, or
+// [OptionalArgument]) take a value only when it is attached, and never
+// consume the next argument, as with ls --color or cc -O:
+//
+//	err := spec.AddLongOptions(argsieve.LongOption{Name: "color", HasArg: argsieve.OptionalArgument})
+//	err = spec.AddFlags(argsieve.OptionalArgument, "-O")
+//	// "--color src -O2 main.c" → remaining ["--color", "-O2"], positional ["src", "main.c"]
+//
+// Flags that take several values, such as "--pos X Y", are declared with
+// [PassthroughSpec.AddFlagsArity]. Use [Config.RequirePassthroughValues]
+// to reject a flag that is missing values:
+//
+//	err := spec.AddFlagsArity(2, "--scale-from")
+//	// "--scale-from 800 600 out" → remaining ["--scale-from", "800", "600"], positional ["out"]
+//
+// Set [Config.StrictPassthrough] as well to reject flags declared in
+// neither the options struct nor the spec, so that typos such as
+// --verbsoe are reported instead of reaching the wrapped command.
+//
 // # Struct Tags
 //
 // Define flags using struct tags:
@@ -41,6 +115,79 @@
 //	    Verbose bool   `short:"v" long:"verbose"`
 //	}
 //
+// # Argument Order
+//
+// [Sift] splits arguments into remaining and positional, losing their
+// interleaving. [SiftDetailed] additionally returns every argument in its
+// original order, tagged as known, passthrough, passthrough value,
+// positional or delimiter, so wrappers of order-sensitive tools can
+// rebuild argv faithfully:
+//
+//	res, err := argsieve.SiftDetailed(&opts, args, nil, nil)
+//	for _, arg := range res.Args {
+//	    if arg.Kind != argsieve.ArgKnown {
+//	        forwarded = append(forwarded, arg.Value)
+//	    }
+//	}
+//
+// The result also separates positional arguments before and after the
+// "--" delimiter and reports whether it was seen. Set
+// [Config.KeepDelimiter] to have "--" forwarded in remaining:
+//
+//	cfg := &argsieve.Config{KeepDelimiter: true}
+//	res, err := argsieve.SiftDetailed(&opts, args, nil, cfg)
+//	// "a -x -- b" → remaining ["-x", "--"], BeforeDelimiter ["a"], AfterDelimiter ["b"]
+//
+// Use [Config.Delimiters] for wrappers whose delimiter is not "--", such
+// as "-args" for go test. [Result.Sections] splits the positional
+// arguments at every delimiter:
+//
+//	cfg := &argsieve.Config{Delimiters: []string{"--", "::"}}
+//	res, err := argsieve.SiftDetailed(&opts, args, nil, cfg)
+//	// "img -- sh :: -c" → Sections [{"" [img]} {"--" [sh]} {"::" [-c]}]
+//
+// # Forwarding Known Flags
+//
+// Tag a field `forward:"<flag>"` to have [Sift] both bind the flag and
+// pass it on to the wrapped command under another spelling. The
+// translated flag and its value are appended to remaining in their
+// original position relative to other forwarded flags:
+//
+//	type Options struct {
+//	    Profile string `long:"profile" forward:"-p"`
+//	}
+//	// "--profile dev -x" → Profile "dev", remaining ["-p", "dev", "-x"]
+//
+// # Values From Files
+//
+// Tag a field `fromfile:"true"` to let its value be read from a file, so
+// secrets need not appear on the command line. A value of the form @path
+// or file:path is replaced by the contents of the file with surrounding
+// whitespace trimmed; the path "-" reads [Config.Stdin]:
+//
+//	type Options struct {
+//	    Token string `long:"token" fromfile:"true"`
+//	}
+//	// "--token @/run/secrets/token" → Token holds the file contents
+//
+// Such fields cannot be used with [Config.ResponseFiles], which would
+// expand @path into arguments before the field reads it.
+//
+// # Hooks
+//
+// Set [Config.Hook] to react to flags as they are parsed, for example to
+// load a file named by --config before later flags override its values.
+// The hook runs after each known flag is set and receives the flag's
+// metadata and raw value. Returning [ErrStop] ends parsing and returns
+// the remaining arguments as positional; any other error aborts parsing:
+//
+//	cfg := &argsieve.Config{Hook: argsieve.FlagHookFunc(func(flag argsieve.Flag, value string) error {
+//	    if flag.Long == "config" {
+//	        return loadDefaults(&opts, value)
+//	    }
+//	    return nil
+//	})}
+//
 // # Supported Flag Formats
 //
 //   - Short flags: -v, -r value, -rvalue, -vdr (chained bools)
@@ -52,6 +199,44 @@
 //   - bool: flag presence sets true (no value required)
 //   - string: requires a value
 //   - [encoding.TextUnmarshaler]: custom parsing (pointer types are nil when absent)
+//   - [flag.Value]: set with Set, for types written for the flag package;
+//     types reporting IsBoolFlag are boolean flags (see below)
+//   - integer types: only on a field tagged `numeric:"true"`
+//   - any type: with a [ValueParser] registered in [Config.TypeParsers] or
+//     [Config.FieldParsers]
+//
+// A field whose pointer implements flag.Value, or a pointer field whose type
+// does, is set by calling Set with each value, so values that accumulate
+// (such as lists) work as with the flag package. If the type has an
+// IsBoolFlag method reporting true, the flag takes no value and Set is
+// called with "true", unless a value is attached as in --name=false or
+// -n=false. Types that also implement encoding.TextUnmarshaler are parsed
+// with UnmarshalText.
+//
+// Registered parsers take precedence over the built-in parsing, so they
+// can also change how a supported type is parsed:
+//
+//	cfg := &argsieve.Config{TypeParsers: map[reflect.Type]argsieve.ValueParser{
+//	    reflect.TypeFor[[]string](): func(value string) (any, error) {
+//	        return strings.Split(value, ","), nil
+//	    },
+//	}}
+//
+// # Numeric Shorthand
+//
+// A single integer field may be tagged `numeric:"true"` to receive
+// -<digits> shorthand, as in head -5 or kill -9:
+//
+//	type Options struct {
+//	    Lines int `short:"n" long:"lines" numeric:"true"`
+//	}
+//	// "-20" and "-n 20" both set Lines to 20
+//
+// Such a struct cannot also declare short names made of digits, like
+// `short:"4"`, since -4 would always set the numeric field.
+//
+// Use [Config.NegativeNumbersPositional] to treat other arguments that parse
+// as negative numbers (-5, -0.25) as positional rather than flags.
 //
 // # Embedded Structs
 //
@@ -65,6 +250,66 @@
 //	    Output string `short:"o"`
 //	}
 //
+// A flag declared twice is bound to the field declared last, so a struct
+// can take over a flag of a struct it embeds. [Validate] reports such
+// duplicates, for structs that do not intend them.
+//
+// # Marshaling
+//
+// [Marshal] is the inverse of [Parse]: it turns the non-zero fields of an
+// options struct back into arguments, for re-invoking the program or a
+// sibling tool with the same settings:
+//
+//	args, err := argsieve.Marshal(&opts, nil)
+//	// opts{Region: "eu", Verbose: true} → ["--region=eu", "--verbose"]
+//
+// # Introspection
+//
+// [SchemaOf] and [Parser.Schema] describe the declared flags, for tools
+// that generate documentation or wrappers:
+//
+//	schema, err := argsieve.SchemaOf((*Options)(nil))
+//	for flag := range schema.Flags() {
+//	    fmt.Println(flag.Short, flag.Long, flag.Field, flag.Type, flag.Arity)
+//	}
+//
+// [ExportJSON] and [ExportJSONSchema] export the same information as JSON
+// and as a JSON Schema (draft 2020-12) document, with defaults taken from
+// the target's current field values.
+//
+// # Tokenizing
+//
+// [Tokenize] and [Lexer] split arguments into long flags, short clusters,
+// attached values, the "--" delimiter and positionals using the rules
+// parsing itself uses, for tooling that does not bind to a struct. The
+// lexer cannot know which flags take values; call [Lexer.NextArg] to read
+// the next argument as one:
+//
+//	lex := argsieve.NewLexer(args)
+//	for tok, ok := lex.Next(); ok; tok, ok = lex.Next() {
+//	    if tok.Kind == argsieve.TokenLong && tok.Name == "output" {
+//	        value, _ := lex.NextArg()
+//	        ...
+//	    }
+//	}
+//
+// # Standard Library flag Package
+//
+// Programs migrating from the flag package can keep their existing flags.
+// Set [Config.FlagSet] to accept the flags of a [flag.FlagSet] alongside
+// those of the options struct, as -name or --name:
+//
+//	timeout := fs.Duration("timeout", 0, "request timeout")
+//	cfg := &argsieve.Config{FlagSet: fs}
+//	positional, err := argsieve.Parse(&opts, args, cfg)
+//	// "-v --timeout 5s" → Verbose true, *timeout 5s
+//
+// Conversely, [RegisterFlags] defines the options struct's flags in a
+// FlagSet, so code that parses with the flag package sets its fields:
+//
+//	argsieve.RegisterFlags(flag.CommandLine, &opts, nil)
+//	flag.Parse()
+//
 // # Error Handling
 //
 // Parse errors are wrapped with [ErrParse] for easy detection:
@@ -73,4 +318,15 @@
 //	    fmt.Fprintln(os.Stderr, err)
 //	    os.Exit(1)
 //	}
+//
+// Unknown flags are reported as an [*UnknownOptionError], which also
+// matches ErrParse and carries the offending flag.
+//
+// Invalid options structs are reported with [ErrSchema]: as a panic by
+// functions that take a target, or as an error by [ParseAs] and [SiftAs].
+// Use [Validate] in unit tests to report every schema problem at once:
+//
+//	if err := argsieve.Validate((*Options)(nil), nil); err != nil {
+//	    t.Fatal(err)
+//	}
 package argsieve
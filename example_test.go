@@ -2,7 +2,10 @@ package argsieve_test
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/ivoronin/argsieve"
 )
@@ -208,3 +211,267 @@ func ExampleConfig_stopAtFirstPositional() {
 	// Verbose: true
 	// Positional: [cmd -x --flag]
 }
+
+func ExampleConfig_singleDashLong() {
+	type Options struct {
+		Chdir string `long:"chdir"`
+	}
+
+	var opts Options
+	// Go flag style: long names with a single dash, unknown ones forwarded intact
+	args := []string{"-chdir=infra", "-var", "region=eu", "plan"}
+
+	cfg := &argsieve.Config{SingleDashLong: true}
+	remaining, positional, err := argsieve.Sift(&opts, args, []string{"-var"}, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Chdir: %s\n", opts.Chdir)
+	fmt.Printf("Passthrough: %v\n", remaining)
+	fmt.Printf("Positional: %v\n", positional)
+	// Output:
+	// Chdir: infra
+	// Passthrough: [-var region=eu]
+	// Positional: [plan]
+}
+
+func ExampleParse_numericShorthand() {
+	type Options struct {
+		Lines int `short:"n" long:"lines" numeric:"true"`
+	}
+
+	var opts Options
+	args := []string{"-20", "access.log"}
+
+	positional, err := argsieve.Parse(&opts, args, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Lines: %d\n", opts.Lines)
+	fmt.Printf("Files: %v\n", positional)
+	// Output:
+	// Lines: 20
+	// Files: [access.log]
+}
+
+func ExampleParseAs() {
+	type Options struct {
+		Output string `short:"o" long:"output"`
+		Force  bool   `short:"f" long:"force"`
+	}
+
+	opts, positional, err := argsieve.ParseAs[Options]([]string{"-f", "-o", "out.txt", "in.txt"}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Output: %s\n", opts.Output)
+	fmt.Printf("Force: %t\n", opts.Force)
+	fmt.Printf("Files: %v\n", positional)
+	// Output:
+	// Output: out.txt
+	// Force: true
+	// Files: [in.txt]
+}
+
+func ExampleSchemaOf() {
+	type Options struct {
+		Region  string `short:"r" long:"region"`
+		Verbose bool   `short:"v" long:"verbose"`
+	}
+
+	schema, err := argsieve.SchemaOf((*Options)(nil))
+	if err != nil {
+		panic(err)
+	}
+
+	for flag := range schema.Flags() {
+		fmt.Printf("-%s, --%s %s arity=%d\n", flag.Short, flag.Long, flag.Type, flag.Arity)
+	}
+	// Output:
+	// -r, --region string arity=1
+	// -v, --verbose bool arity=0
+}
+
+func ExampleMarshal() {
+	type Options struct {
+		Region  string `short:"r" long:"region"`
+		Profile string `short:"p"`
+		Verbose bool   `short:"v" long:"verbose"`
+	}
+
+	opts := Options{Region: "us-west-2", Profile: "dev", Verbose: true}
+
+	args, err := argsieve.Marshal(&opts, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%q\n", args)
+	// Output:
+	// ["--region=us-west-2" "-p" "dev" "--verbose"]
+}
+
+func ExampleSiftDetailed() {
+	type Options struct {
+		Verbose bool `short:"v"`
+	}
+
+	var opts Options
+	// find-style arguments where the order of tests matters
+	args := []string{"-v", "src", "-name", "*.go", "-print"}
+
+	res, err := argsieve.SiftDetailed(&opts, args, []string{"-name"}, &argsieve.Config{SingleDashLong: true})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, arg := range res.Args {
+		fmt.Printf("%d %-17s %s\n", arg.Index, arg.Kind, arg.Value)
+	}
+	// Output:
+	// 0 known             -v
+	// 1 positional        src
+	// 2 passthrough       -name
+	// 3 passthrough-value *.go
+	// 4 passthrough       -print
+}
+
+func ExampleTokenize() {
+	args := []string{"-vx", "--out=a.txt", "-", "--", "-f"}
+
+	for tok := range argsieve.Tokenize(args) {
+		fmt.Printf("%d %-10s %q %q\n", tok.Index, tok.Kind, tok.Name, tok.Value)
+	}
+	// Output:
+	// 0 short      "vx" ""
+	// 1 long       "out" ""
+	// 1 value      "" "a.txt"
+	// 2 positional "" "-"
+	// 3 delimiter  "" ""
+	// 4 positional "" "-f"
+}
+
+func ExamplePassthroughSpec() {
+	type Options struct {
+		Debug bool `long:"debug"`
+	}
+
+	// Options of the wrapped command, from its getopt optstring
+	var spec argsieve.PassthroughSpec
+	if err := spec.AddOptstring("lo:C::"); err != nil {
+		panic(err)
+	}
+
+	var opts Options
+	args := []string{"--debug", "-lo", "out.txt", "-C", "in.txt"}
+
+	remaining, positional, err := argsieve.Sift(&opts, args, nil, &argsieve.Config{Passthrough: &spec})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Debug:", opts.Debug)
+	fmt.Println("Remaining:", remaining)
+	fmt.Println("Positional:", positional)
+	// Output:
+	// Debug: true
+	// Remaining: [-l -o out.txt -C]
+	// Positional: [in.txt]
+}
+
+func ExamplePassthroughSpec_optionalArgument() {
+	type Options struct {
+		Verbose bool `short:"v"`
+	}
+
+	// ls --color takes an optional value that must be attached with "="
+	var spec argsieve.PassthroughSpec
+	if err := spec.AddLongOptions(argsieve.LongOption{Name: "color", HasArg: argsieve.OptionalArgument}); err != nil {
+		panic(err)
+	}
+
+	var opts Options
+	args := []string{"-v", "--color", "src", "--color=never", "docs"}
+
+	remaining, positional, err := argsieve.Sift(&opts, args, nil, &argsieve.Config{Passthrough: &spec})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Remaining:", remaining)
+	fmt.Println("Positional:", positional)
+	// Output:
+	// Remaining: [--color --color=never]
+	// Positional: [src docs]
+}
+
+func ExampleFlagHookFunc() {
+	type Options struct {
+		Help    bool `short:"h" long:"help"`
+		Verbose bool `short:"v"`
+	}
+
+	// Stop at --help and leave the rest of the arguments alone
+	cfg := &argsieve.Config{Hook: argsieve.FlagHookFunc(func(flag argsieve.Flag, value string) error {
+		if flag.Long == "help" {
+			return argsieve.ErrStop
+		}
+
+		return nil
+	})}
+
+	var opts Options
+	positional, err := argsieve.Parse(&opts, []string{"-v", "--help", "--bogus"}, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Help:", opts.Help)
+	fmt.Println("Positional:", positional)
+	// Output:
+	// Help: true
+	// Positional: [--bogus]
+}
+
+func ExampleValueParser() {
+	type Options struct {
+		Tags []string `long:"tags"`
+	}
+
+	cfg := &argsieve.Config{TypeParsers: map[reflect.Type]argsieve.ValueParser{
+		reflect.TypeFor[[]string](): func(value string) (any, error) {
+			return strings.Split(value, ","), nil
+		},
+	}}
+
+	var opts Options
+	if _, err := argsieve.Parse(&opts, []string{"--tags", "web,db"}, cfg); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%q\n", opts.Tags)
+	// Output:
+	// ["web" "db"]
+}
+
+func ExampleRegisterFlags() {
+	type Options struct {
+		Region  string `short:"r" long:"region"`
+		Verbose bool   `short:"v" long:"verbose"`
+	}
+
+	var opts Options
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	argsieve.RegisterFlags(fs, &opts, nil)
+
+	if err := fs.Parse([]string{"-region", "eu", "-v", "file"}); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(opts.Region, opts.Verbose, fs.Args())
+	// Output:
+	// eu true [file]
+}
@@ -0,0 +1,223 @@
+package argsieve
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// jsonSchemaDialect is the JSON Schema draft produced by [ExportJSONSchema].
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// exportedSchema is the document produced by [ExportJSON].
+type exportedSchema struct {
+	Type  string         `json:"type"`
+	Flags []exportedFlag `json:"flags"`
+}
+
+// exportedFlag describes one flag in [ExportJSON] output.
+type exportedFlag struct {
+	Field    string `json:"field"`
+	Short    string `json:"short,omitempty"`
+	Long     string `json:"long,omitempty"`
+	Type     string `json:"type"`
+	GoType   string `json:"goType"`
+	Arity    int    `json:"arity"`
+	Numeric  bool   `json:"numeric,omitempty"`
+	FromFile bool   `json:"fromFile,omitempty"`
+	Default  any    `json:"default,omitempty"`
+}
+
+// jsonSchema is the document produced by [ExportJSONSchema].
+type jsonSchema struct {
+	Schema               string                        `json:"$schema"`
+	Title                string                        `json:"title"`
+	Type                 string                        `json:"type"`
+	Properties           map[string]jsonSchemaProperty `json:"properties"`
+	AdditionalProperties bool                          `json:"additionalProperties"`
+}
+
+// jsonSchemaProperty describes one flag in [ExportJSONSchema] output.
+type jsonSchemaProperty struct {
+	Type    string   `json:"type"`
+	Default any      `json:"default,omitempty"`
+	Flags   []string `json:"x-argsieve-flags"`
+}
+
+// ExportJSON describes the options struct target points to as JSON, for
+// tools such as web forms and config validators built over a CLI.
+//
+// The document lists every flag with its field path, names, JSON type
+// ("boolean", "integer" or "string"), Go type, arity and tag options.
+// Defaults are taken from the current non-zero field values of target,
+// the same values a flag leaves untouched when it is absent; fields of
+// custom types need [encoding.TextMarshaler] to report a default. A nil
+// target pointer reports no defaults.
+//
+// Example:
+//
+//	opts := Options{Region: "us-east-1"}
+//	data, err := argsieve.ExportJSON(&opts)
+//	// {"type":"Options","flags":[{"field":"Region","short":"r","long":"region",
+//	//   "type":"string","goType":"string","arity":1,"default":"us-east-1"}]}
+//
+// Returns an error wrapping [ErrSchema] if target is not a pointer to
+// struct or the struct is invalid (see [Validate]).
+func ExportJSON(target any) ([]byte, error) {
+	schema, err := SchemaOf(target)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := exportedSchema{Type: schema.Type().Name(), Flags: []exportedFlag{}}
+
+	for flag := range schema.Flags() {
+		def, err := flagDefault(target, flag)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Flags = append(doc.Flags, exportedFlag{
+			Field:    flag.Field,
+			Short:    flag.Short,
+			Long:     flag.Long,
+			Type:     jsonType(flag.Type),
+			GoType:   flag.Type.String(),
+			Arity:    flag.Arity,
+			Numeric:  flag.Numeric,
+			FromFile: flag.FromFile,
+			Default:  def,
+		})
+	}
+
+	return json.Marshal(doc)
+}
+
+// ExportJSONSchema describes the options struct target points to as a
+// JSON Schema (draft 2020-12) object. Each flag is a property named by its
+// long name, or its short name or field path if it has none; the property
+// carries the flag's JSON type, its default (as in [ExportJSON]) and the
+// flag spellings in the "x-argsieve-flags" annotation.
+//
+// Returns an error wrapping [ErrSchema] if target is not a pointer to
+// struct, the struct is invalid (see [Validate]), or two flags would be
+// the same property, such as one with long:"x" and one with only short:"x".
+func ExportJSONSchema(target any) ([]byte, error) {
+	schema, err := SchemaOf(target)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := jsonSchema{
+		Schema:     jsonSchemaDialect,
+		Title:      schema.Type().Name(),
+		Type:       "object",
+		Properties: make(map[string]jsonSchemaProperty),
+	}
+
+	for flag := range schema.Flags() {
+		def, err := flagDefault(target, flag)
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		if flag.Short != "" {
+			names = append(names, "-"+flag.Short)
+		}
+		if flag.Long != "" {
+			names = append(names, "--"+flag.Long)
+		}
+		if flag.Numeric {
+			names = append(names, "-<digits>")
+		}
+
+		key := flag.Field
+		switch {
+		case flag.Long != "":
+			key = flag.Long
+		case flag.Short != "":
+			key = flag.Short
+		}
+
+		if _, dup := doc.Properties[key]; dup {
+			return nil, fmt.Errorf("%w: property %q of field %s is already used by another flag", ErrSchema, key, flag.Field)
+		}
+
+		doc.Properties[key] = jsonSchemaProperty{
+			Type:    jsonType(flag.Type),
+			Default: def,
+			Flags:   names,
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// jsonType returns the JSON type of values of a flag field type.
+func jsonType(t reflect.Type) string {
+	switch {
+	case isFlagValue(t) && isBoolFlag(t):
+		return "boolean"
+	case isFlagValue(t):
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case isIntKind(t.Kind()) && !reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return "integer"
+	default:
+		return "string"
+	}
+}
+
+// flagDefault returns the current value of flag's field in target as a
+// JSON-encodable value, or nil if the field is zero or target is nil.
+func flagDefault(target any, flag Flag) (any, error) {
+	v := reflect.ValueOf(target)
+	if v.IsNil() {
+		return nil, nil
+	}
+
+	field := v.Elem().FieldByIndex(flag.Index)
+	if field.IsZero() {
+		return nil, nil
+	}
+
+	if text, ok, err := marshalText(field); ok {
+		if err != nil {
+			return nil, fmt.Errorf("argsieve: default of field %s: %w", flag.Field, err)
+		}
+
+		return text, nil
+	}
+
+	if isFlagValue(field.Type()) {
+		text := flagValue(field).String()
+		if isBoolFlag(field.Type()) {
+			b, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, nil // not a boolean value
+			}
+
+			return b, nil
+		}
+
+		return text, nil
+	}
+
+	if field.Kind() == reflect.Ptr || reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		return nil, nil // custom type without TextMarshaler
+	}
+
+	switch kind := field.Kind(); {
+	case kind == reflect.Bool:
+		return field.Bool(), nil
+	case isIntKind(kind) && field.CanInt():
+		return field.Int(), nil
+	case isIntKind(kind):
+		return field.Uint(), nil
+	default:
+		return field.String(), nil
+	}
+}
@@ -0,0 +1,137 @@
+package argsieve
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportLevel implements both encoding.TextMarshaler and TextUnmarshaler.
+type exportLevel string
+
+func (l *exportLevel) UnmarshalText(text []byte) error { *l = exportLevel(text); return nil }
+func (l exportLevel) MarshalText() ([]byte, error) {
+	if l == "broken" {
+		return nil, errors.New("cannot marshal")
+	}
+	return []byte(l), nil
+}
+
+type exportFlags struct {
+	testEmbeddedBase
+	Lines   int          `short:"n" numeric:"true"`
+	Level   exportLevel  `long:"level"`
+	Ptr     *exportLevel `long:"ptr"`
+	Custom  logLevel     `long:"custom"`
+	Token   string       `long:"token" fromfile:"true"`
+	Verbose bool         `short:"v"`
+	Ignored string
+}
+
+func TestExportJSON(t *testing.T) {
+	t.Parallel()
+
+	opts := exportFlags{
+		Lines:   10,
+		Level:   "info",
+		Ptr:     ptrTo(exportLevel("warn")),
+		Custom:  logLevelDebug,
+		Verbose: true,
+		Ignored: "x",
+	}
+	opts.Region = "us-east-1"
+
+	data, err := ExportJSON(&opts)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "exportFlags",
+		"flags": [
+			{"field": "testEmbeddedBase.Region", "short": "r", "long": "region", "type": "string",
+			 "goType": "string", "arity": 1, "default": "us-east-1"},
+			{"field": "Lines", "short": "n", "type": "integer", "goType": "int", "arity": 1,
+			 "numeric": true, "default": 10},
+			{"field": "Level", "long": "level", "type": "string", "goType": "argsieve.exportLevel",
+			 "arity": 1, "default": "info"},
+			{"field": "Ptr", "long": "ptr", "type": "string", "goType": "*argsieve.exportLevel",
+			 "arity": 1, "default": "warn"},
+			{"field": "Custom", "long": "custom", "type": "string", "goType": "argsieve.logLevel", "arity": 1},
+			{"field": "Token", "long": "token", "type": "string", "goType": "string", "arity": 1,
+			 "fromFile": true},
+			{"field": "Verbose", "short": "v", "type": "boolean", "goType": "bool", "arity": 0,
+			 "default": true}
+		]
+	}`, string(data))
+}
+
+func TestExportJSON_NilTarget(t *testing.T) {
+	t.Parallel()
+
+	data, err := ExportJSON((*testFlags)(nil))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "testFlags",
+		"flags": [
+			{"field": "Region", "short": "r", "long": "region", "type": "string", "goType": "string", "arity": 1},
+			{"field": "Profile", "short": "p", "long": "profile", "type": "string", "goType": "string", "arity": 1},
+			{"field": "Verbose", "short": "v", "long": "verbose", "type": "boolean", "goType": "bool", "arity": 0},
+			{"field": "Debug", "short": "d", "long": "debug", "type": "boolean", "goType": "bool", "arity": 0}
+		]
+	}`, string(data))
+}
+
+func TestExportJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	type options struct {
+		Region  string `short:"r" long:"region"`
+		Verbose bool   `short:"v"`
+		Lines   uint   `numeric:"true"`
+	}
+
+	opts := options{Region: "us-east-1"}
+
+	data, err := ExportJSONSchema(&opts)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "options",
+		"type": "object",
+		"properties": {
+			"region": {"type": "string", "default": "us-east-1", "x-argsieve-flags": ["-r", "--region"]},
+			"v": {"type": "boolean", "x-argsieve-flags": ["-v"]},
+			"Lines": {"type": "integer", "x-argsieve-flags": ["-<digits>"]}
+		},
+		"additionalProperties": false
+	}`, string(data))
+}
+
+func TestExport_Errors(t *testing.T) {
+	t.Parallel()
+
+	type badStruct struct {
+		Count float64 `short:"c"`
+	}
+
+	_, err := ExportJSON((*badStruct)(nil))
+	assert.ErrorIs(t, err, ErrSchema)
+
+	_, err = ExportJSONSchema(testFlags{})
+	assert.ErrorIs(t, err, ErrSchema)
+
+	type collidingStruct struct {
+		Exclude string `long:"x"`
+		Extra   bool   `short:"x"`
+	}
+
+	_, err = ExportJSONSchema((*collidingStruct)(nil))
+	assert.ErrorIs(t, err, ErrSchema)
+	assert.ErrorContains(t, err, `property "x" of field Extra`)
+
+	_, err = ExportJSON(&exportFlags{Level: "broken"})
+	assert.ErrorContains(t, err, "default of field Level")
+}
@@ -0,0 +1,185 @@
+package argsieve
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// bindFlagSet sets a flag of [Config.FlagSet] named name from its attached
+// value or the next argument, following the flag package: boolean flags
+// take a value only when it is attached. Reports false if there is no
+// FlagSet or it has no flag named name.
+func (s *sieve) bindFlagSet(name, flagName, eqValue string, hasEquals bool, next func() (string, bool)) (bool, error) {
+	if s.cfg.FlagSet == nil {
+		return false, nil
+	}
+
+	f := s.cfg.FlagSet.Lookup(name)
+	if f == nil {
+		return false, nil
+	}
+
+	value := eqValue
+
+	if !hasEquals {
+		if bf, ok := f.Value.(boolFlag); ok && bf.IsBoolFlag() {
+			value = "true"
+		} else if value, ok = next(); !ok {
+			return true, fmt.Errorf("%w: missing value for %s", ErrParse, flagName)
+		}
+	}
+
+	if err := s.cfg.FlagSet.Set(name, value); err != nil {
+		return true, fmt.Errorf("%w: invalid value for %s: %v", ErrParse, flagName, err)
+	}
+
+	s.markKnown(s.argIndex)
+	s.markKnown(s.pos)
+
+	return true, nil
+}
+
+// handleFlagSetShort processes -name or -name=value arguments naming a
+// flag of [Config.FlagSet], unless the struct declares a flag under that
+// name. Reports false if arg should be parsed as struct flags instead.
+func (s *sieve) handleFlagSetShort(arg string, next func() (string, bool)) (bool, error) {
+	name, eqValue, hasEquals := strings.Cut(arg[1:], "=")
+
+	if _, known := s.lookup("-" + name); known {
+		return false, nil
+	}
+
+	if _, known := s.lookup("--" + name); known && s.cfg.SingleDashLong {
+		return false, nil
+	}
+
+	return s.bindFlagSet(name, "-"+name, eqValue, hasEquals, next)
+}
+
+// RegisterFlags defines a flag in fs for each short and long name of the
+// options struct target points to, so that parsing with fs sets the
+// struct's fields. It lets code built on the flag package adopt argsieve
+// options structs one at a time.
+//
+// Values are parsed as by [Parse] with the given configuration. Boolean
+// fields are boolean flags that also accept -name=false, as in the flag
+// package. Flags have no usage text.
+//
+// Example:
+//
+//	var opts Options
+//	argsieve.RegisterFlags(flag.CommandLine, &opts, nil)
+//	flag.Parse()
+//
+// Panics if target is not a pointer to struct, if any tagged field has an
+// unsupported type, or if fs already defines one of the names.
+func RegisterFlags(fs *flag.FlagSet, target any, cfg *Config) {
+	NewParser(target, cfg).RegisterFlags(fs, target)
+}
+
+// RegisterFlags is like the package-level [RegisterFlags] using the Parser's
+// configuration.
+//
+// Panics if target is not a non-nil pointer to the Parser's struct type or
+// if fs already defines one of the names.
+func (p *Parser) RegisterFlags(fs *flag.FlagSet, target any) {
+	s := p.newSieve(target, true)
+
+	for _, spec := range s.schema.fields {
+		var v flag.Value = &fieldValue{s: s, info: s.bind(spec)}
+		if fv := v.(*fieldValue); fv.isBool() {
+			v = &boolFieldValue{*fv}
+		}
+
+		if spec.short != "" {
+			fs.Var(v, spec.short, "")
+		}
+
+		if spec.long != "" {
+			fs.Var(v, spec.long, "")
+		}
+	}
+}
+
+// fieldValue is a flag.Value that sets an options struct field.
+type fieldValue struct {
+	s    *sieve
+	info fieldInfo
+}
+
+// String returns the field's value as text, or "" if it has none.
+func (v *fieldValue) String() string {
+	if v.s == nil {
+		return "" // zero value created by flag.PrintDefaults
+	}
+
+	if v.isBool() {
+		return strconv.FormatBool(v.info.field.Bool())
+	}
+
+	if v.info.field.IsZero() {
+		return ""
+	}
+
+	// Show lists and other accumulated values, which Marshal rejects
+	if v.info.value && v.info.needsArg {
+		return flagValue(v.info.field).String()
+	}
+
+	text, err := formatField(v.info)
+	if err != nil {
+		return ""
+	}
+
+	return text
+}
+
+// Set sets the field from value.
+func (v *fieldValue) Set(value string) error {
+	switch {
+	case v.isBool():
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		v.info.field.SetBool(b)
+
+		return nil
+	case v.info.value && !v.info.needsArg:
+		return flagValue(v.info.field).Set(value)
+	default:
+		return v.s.setField(v.info, value)
+	}
+}
+
+// IsBoolFlag reports whether the field takes no value.
+func (v *fieldValue) IsBoolFlag() bool {
+	return v.s != nil && !v.info.needsArg
+}
+
+// boolFieldValue is the fieldValue of a plain bool field. Its zero value
+// reports "false", as the flag package expects of a boolean flag when it
+// decides whether to print the default in usage.
+type boolFieldValue struct {
+	fieldValue
+}
+
+// String returns the field's value as text, or "false" if it has none.
+func (v *boolFieldValue) String() string {
+	if v.s == nil {
+		return "false" // zero value created by flag.PrintDefaults
+	}
+
+	return v.fieldValue.String()
+}
+
+// isBool reports whether the field is a plain bool set without a parser.
+func (v *fieldValue) isBool() bool {
+	_, parsed := v.s.cfg.valueParser(v.info.fieldSpec)
+
+	return !v.info.needsArg && !v.info.value && !parsed && v.info.field.Kind() == reflect.Bool
+}
@@ -0,0 +1,254 @@
+package argsieve
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyFlags is a flag.FlagSet defined without argsieve.
+type legacyFlags struct {
+	fs      *flag.FlagSet
+	name    *string
+	debug   *bool
+	timeout *time.Duration
+}
+
+// legacyOpts is an options struct used alongside legacyFlags.
+type legacyOpts struct {
+	Region  string `short:"r" long:"region"`
+	Verbose bool   `short:"v" long:"verbose"`
+	Debug   bool   `short:"d"`
+}
+
+func newLegacyFlags() *legacyFlags {
+	fs := flag.NewFlagSet("legacy", flag.ContinueOnError)
+
+	return &legacyFlags{
+		fs:      fs,
+		name:    fs.String("name", "", "name"),
+		debug:   fs.Bool("debug", false, "debug"),
+		timeout: fs.Duration("timeout", 0, "timeout"),
+	}
+}
+
+func TestSift_FlagSet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args          []string
+		wantName      string
+		wantDebug     bool
+		wantTimeout   time.Duration
+		wantOpts      legacyOpts
+		wantRemaining []string
+		wantPos       []string
+	}{
+		"single dash with next value": {
+			args:     []string{"-name", "web", "file"},
+			wantName: "web",
+			wantPos:  []string{"file"},
+		},
+		"double dash with equals": {
+			args:        []string{"--timeout=5s", "--name=db"},
+			wantName:    "db",
+			wantTimeout: 5 * time.Second,
+		},
+		"bool flag does not take next value": {
+			args:      []string{"-debug", "false"},
+			wantDebug: true,
+			wantPos:   []string{"false"},
+		},
+		"bool flag with equals": {
+			args: []string{"--debug=false"},
+		},
+		"mixed with struct and unknown flags": {
+			args:          []string{"-v", "-name", "x", "--region", "eu", "-z"},
+			wantName:      "x",
+			wantOpts:      legacyOpts{Verbose: true, Region: "eu"},
+			wantRemaining: []string{"-z"},
+		},
+		"struct flags take precedence": {
+			args:     []string{"-d", "--verbose"},
+			wantOpts: legacyOpts{Debug: true, Verbose: true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			legacy := newLegacyFlags()
+			shadowed := legacy.fs.Bool("d", false, "shadowed by the struct")
+			legacy.fs.Bool("verbose", false, "shadowed by the struct")
+
+			var opts legacyOpts
+			remaining, positional, err := Sift(&opts, tt.args, nil, &Config{FlagSet: legacy.fs})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, *legacy.name)
+			assert.Equal(t, tt.wantDebug, *legacy.debug)
+			assert.Equal(t, tt.wantTimeout, *legacy.timeout)
+			assert.Equal(t, tt.wantOpts, opts)
+			assert.Equal(t, tt.wantRemaining, remaining)
+			assert.Equal(t, tt.wantPos, positional)
+			assert.False(t, *shadowed)
+		})
+	}
+}
+
+func TestParse_FlagSetErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"missing value": {
+			args:    []string{"-name"},
+			wantErr: "missing value for -name",
+		},
+		"invalid value": {
+			args:    []string{"--timeout", "soon"},
+			wantErr: "invalid value for --timeout",
+		},
+		"unknown flag": {
+			args:    []string{"-other"},
+			wantErr: "unknown option -o",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts legacyOpts
+			_, err := Parse(&opts, tt.args, &Config{FlagSet: newLegacyFlags().fs})
+
+			require.ErrorIs(t, err, ErrParse)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSiftDetailed_FlagSet(t *testing.T) {
+	t.Parallel()
+
+	var opts legacyOpts
+	res, err := SiftDetailed(&opts, []string{"-name", "x", "-z"}, nil, &Config{FlagSet: newLegacyFlags().fs})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Arg{
+		{Value: "-name", Kind: ArgKnown, Index: 0},
+		{Value: "x", Kind: ArgKnown, Index: 1},
+		{Value: "-z", Kind: ArgPassthrough, Index: 2},
+	}, res.Args)
+}
+
+func TestRegisterFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args    []string
+		want    flagValueFlags
+		wantPos []string
+	}{
+		"short and long names": {
+			args: []string{"-I", "a", "--include=b", "-d"},
+			want: flagValueFlags{Include: listValue{"a", "b"}, Debug: true},
+		},
+		"bool field with value": {
+			args: []string{"-d=false", "--color=true"},
+			want: flagValueFlags{Color: true},
+		},
+		"bool flag.Value": {
+			args:    []string{"-v", "-v", "file"},
+			want:    flagValueFlags{Verbose: 2},
+			wantPos: []string{"file"},
+		},
+		"pointer flag.Value": {
+			args: []string{"-proxy", "p:3128"},
+			want: flagValueFlags{Proxy: &hostPort{Host: "p", Port: 3128}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts flagValueFlags
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			RegisterFlags(fs, &opts, nil)
+
+			require.NoError(t, fs.Parse(tt.args))
+			assert.Equal(t, tt.want, opts)
+
+			if tt.wantPos == nil {
+				assert.Empty(t, fs.Args())
+			} else {
+				assert.Equal(t, tt.wantPos, fs.Args())
+			}
+		})
+	}
+}
+
+func TestRegisterFlags_InvalidValue(t *testing.T) {
+	t.Parallel()
+
+	var opts flagValueFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	RegisterFlags(fs, &opts, nil)
+
+	assert.Error(t, fs.Parse([]string{"--proxy", "nope"}))
+}
+
+func TestRegisterFlags_Defaults(t *testing.T) {
+	t.Parallel()
+
+	opts := flagValueFlags{Include: listValue{"x"}, Debug: true}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	RegisterFlags(fs, &opts, nil)
+
+	assert.Equal(t, "x", fs.Lookup("include").DefValue)
+	assert.Equal(t, "true", fs.Lookup("d").DefValue)
+	assert.Equal(t, "", fs.Lookup("proxy").DefValue)
+	assert.NotPanics(t, fs.PrintDefaults)
+}
+
+func TestRegisterFlags_Usage(t *testing.T) {
+	t.Parallel()
+
+	type options struct {
+		Region  string `short:"r"`
+		Verbose bool   `short:"v"`
+		Debug   bool   `short:"d"`
+	}
+
+	var buf strings.Builder
+	opts := options{Debug: true}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	RegisterFlags(fs, &opts, nil)
+	fs.PrintDefaults()
+
+	assert.Contains(t, buf.String(), "(default true)")
+	assert.NotContains(t, buf.String(), "(default false)")
+}
+
+func TestRegisterFlags_Conflict(t *testing.T) {
+	t.Parallel()
+
+	var opts flagValueFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Bool("d", false, "")
+
+	assert.Panics(t, func() { RegisterFlags(fs, &opts, nil) })
+}
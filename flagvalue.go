@@ -0,0 +1,63 @@
+package argsieve
+
+import (
+	"flag"
+	"reflect"
+)
+
+// flagValueType is used to check if a type implements flag.Value.
+var flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+
+// boolFlag is implemented by flag.Value types that are boolean flags,
+// as in the flag package.
+type boolFlag interface {
+	IsBoolFlag() bool
+}
+
+// isFlagValue reports whether fields of type t are set through
+// [flag.Value]: t is a pointer implementing it or a type whose pointer
+// implements it. Types that implement [encoding.TextUnmarshaler] are
+// parsed with UnmarshalText instead.
+func isFlagValue(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return t.Implements(flagValueType) && !reflect.PointerTo(t.Elem()).Implements(textUnmarshalerType)
+	}
+
+	return reflect.PointerTo(t).Implements(flagValueType) && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isBoolFlag reports whether a flag.Value field of type t is a boolean
+// flag, asking a zero value of the type.
+func isBoolFlag(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	bf, ok := reflect.New(t).Interface().(boolFlag)
+
+	return ok && bf.IsBoolFlag()
+}
+
+// flagValue returns the flag.Value of a field set through it. A nil
+// pointer field is allocated first.
+func flagValue(field reflect.Value) flag.Value {
+	if field.Kind() != reflect.Ptr {
+		return field.Addr().Interface().(flag.Value)
+	}
+
+	if field.IsNil() {
+		field.Set(reflect.New(field.Type().Elem()))
+	}
+
+	return field.Interface().(flag.Value)
+}
+
+// setFlagValue sets a field through flag.Value. Boolean flags given
+// without a value are set to "true", as the flag package does for -name.
+func setFlagValue(info fieldInfo, value string) error {
+	if !info.needsArg && value == "" {
+		value = "true"
+	}
+
+	return flagValue(info.field).Set(value)
+}
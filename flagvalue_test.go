@@ -0,0 +1,325 @@
+package argsieve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listValue is a flag.Value that collects every value it is set to.
+type listValue []string
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func (l *listValue) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// countValue is a boolean flag.Value counting how often it is set.
+type countValue int
+
+func (c *countValue) String() string { return strconv.Itoa(int(*c)) }
+
+func (c *countValue) Set(string) error {
+	*c++
+	return nil
+}
+
+func (c *countValue) IsBoolFlag() bool { return true }
+
+// hostPort is a flag.Value used through a pointer field.
+type hostPort struct {
+	Host string
+	Port int
+}
+
+func (h *hostPort) String() string { return fmt.Sprintf("%s:%d", h.Host, h.Port) }
+
+func (h *hostPort) Set(value string) error {
+	host, port, ok := strings.Cut(value, ":")
+	if !ok {
+		return errors.New("missing port")
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return err
+	}
+
+	h.Host, h.Port = host, n
+
+	return nil
+}
+
+// switchValue is a boolean flag.Value holding a bool.
+type switchValue bool
+
+func (s *switchValue) String() string { return strconv.FormatBool(bool(*s)) }
+
+func (s *switchValue) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	*s = switchValue(b)
+
+	return err
+}
+
+func (s *switchValue) IsBoolFlag() bool { return true }
+
+// textAndFlagValue implements both encoding.TextUnmarshaler and flag.Value.
+type textAndFlagValue string
+
+func (v *textAndFlagValue) UnmarshalText(text []byte) error {
+	*v = textAndFlagValue("text:" + string(text))
+	return nil
+}
+
+func (v *textAndFlagValue) String() string { return string(*v) }
+
+func (v *textAndFlagValue) Set(value string) error {
+	*v = textAndFlagValue("set:" + value)
+	return nil
+}
+
+// flagValueFlags has fields of flag.Value types.
+type flagValueFlags struct {
+	Include listValue        `short:"I" long:"include"`
+	Verbose countValue       `short:"v"`
+	Proxy   *hostPort        `long:"proxy"`
+	Color   switchValue      `short:"c" long:"color"`
+	Both    textAndFlagValue `long:"both"`
+	Debug   bool             `short:"d"`
+}
+
+func TestParse_FlagValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args           []string
+		want           flagValueFlags
+		wantPositional []string
+	}{
+		"repeated value": {
+			args: []string{"-I", "a", "--include=b", "-Ic"},
+			want: flagValueFlags{Include: listValue{"a", "b", "c"}},
+		},
+		"bool flag chained": {
+			args:           []string{"-vvdv", "file"},
+			want:           flagValueFlags{Verbose: 3, Debug: true},
+			wantPositional: []string{"file"},
+		},
+		"bool flag does not take a value": {
+			args:           []string{"--color", "false"},
+			want:           flagValueFlags{Color: true},
+			wantPositional: []string{"false"},
+		},
+		"bool flag with attached value": {
+			args: []string{"--color", "--color=false"},
+			want: flagValueFlags{},
+		},
+		"bool short flag with attached value": {
+			args: []string{"-dc", "-c=false"},
+			want: flagValueFlags{Debug: true},
+		},
+		"pointer field allocated": {
+			args: []string{"--proxy", "p:3128"},
+			want: flagValueFlags{Proxy: &hostPort{Host: "p", Port: 3128}},
+		},
+		"text unmarshaler preferred": {
+			args: []string{"--both", "x"},
+			want: flagValueFlags{Both: "text:x"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts flagValueFlags
+			positional, err := Parse(&opts, tt.args, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, opts)
+			assert.Equal(t, tt.wantPositional, positional)
+		})
+	}
+}
+
+func TestParse_FlagValueSingleDashLong(t *testing.T) {
+	t.Parallel()
+
+	opts := flagValueFlags{Color: true}
+	_, err := Parse(&opts, []string{"-color=false", "-d"}, &Config{SingleDashLong: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, flagValueFlags{Debug: true}, opts)
+
+	opts = flagValueFlags{Color: true}
+	_, err = Parse(&opts, []string{"-c=false"}, &Config{SingleDashLong: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, flagValueFlags{}, opts)
+}
+
+func TestSift_FlagValueForward(t *testing.T) {
+	t.Parallel()
+
+	type options struct {
+		Color switchValue `short:"c" long:"color" forward:"--colour"`
+	}
+
+	tests := map[string]struct {
+		args          []string
+		want          switchValue
+		wantRemaining []string
+	}{
+		"without value":    {args: []string{"--color"}, want: true, wantRemaining: []string{"--colour"}},
+		"with long value":  {args: []string{"--color=false"}, want: false, wantRemaining: []string{"--colour=false"}},
+		"with short value": {args: []string{"-c=true"}, want: true, wantRemaining: []string{"--colour=true"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := options{Color: !tt.want}
+			remaining, _, err := Sift(&opts, tt.args, nil, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, opts.Color)
+			assert.Equal(t, tt.wantRemaining, remaining)
+		})
+	}
+}
+
+func TestParse_FlagValuePointerReused(t *testing.T) {
+	t.Parallel()
+
+	proxy := &hostPort{}
+	opts := flagValueFlags{Proxy: proxy}
+
+	_, err := Parse(&opts, []string{"--proxy", "a:1"}, nil)
+
+	require.NoError(t, err)
+	assert.Same(t, proxy, opts.Proxy)
+	assert.Equal(t, "a", proxy.Host)
+}
+
+func TestParse_FlagValueError(t *testing.T) {
+	t.Parallel()
+
+	var opts flagValueFlags
+	_, err := Parse(&opts, []string{"--proxy", "nope"}, nil)
+
+	require.ErrorIs(t, err, ErrParse)
+	assert.ErrorContains(t, err, "invalid value for --proxy: missing port")
+}
+
+func TestSchemaOf_FlagValue(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaOf((*flagValueFlags)(nil))
+	require.NoError(t, err)
+
+	arity := make(map[string]int)
+	for flag := range schema.Flags() {
+		arity[flag.Field] = flag.Arity
+	}
+
+	assert.Equal(t, map[string]int{
+		"Include": 1, "Verbose": 0, "Proxy": 1, "Color": 0, "Both": 1, "Debug": 0,
+	}, arity)
+}
+
+func TestMarshal_FlagValue(t *testing.T) {
+	t.Parallel()
+
+	opts := flagValueFlags{
+		Include: listValue{"a"},
+		Verbose: 1,
+		Proxy:   &hostPort{Host: "p", Port: 1},
+	}
+
+	args, err := Marshal(&opts, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--include=a", "-v", "--proxy=p:1"}, args)
+
+	var parsed flagValueFlags
+	_, err = Parse(&parsed, args, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, listValue{"a"}, parsed.Include)
+	assert.Equal(t, countValue(1), parsed.Verbose)
+	assert.Equal(t, opts.Proxy, parsed.Proxy)
+}
+
+func TestMarshal_FlagValueRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts    flagValueFlags
+		wantErr string
+	}{
+		"single value":      {opts: flagValueFlags{Include: listValue{"a"}}},
+		"pointer value":     {opts: flagValueFlags{Proxy: &hostPort{Host: "p", Port: 1}}},
+		"bool value":        {opts: flagValueFlags{Color: true}},
+		"multiple values":   {opts: flagValueFlags{Include: listValue{"a", "b"}}, wantErr: "field Include"},
+		"counter set once":  {opts: flagValueFlags{Verbose: 1}},
+		"counter set twice": {opts: flagValueFlags{Verbose: 2}, wantErr: "field Verbose"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			args, err := Marshal(&tt.opts, nil)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var parsed flagValueFlags
+			_, err = Parse(&parsed, args, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.opts, parsed)
+		})
+	}
+}
+
+func TestExportJSON_FlagValue(t *testing.T) {
+	t.Parallel()
+
+	opts := flagValueFlags{Include: listValue{"a", "b"}, Color: true}
+
+	data, err := ExportJSON(&opts)
+	require.NoError(t, err)
+
+	var doc struct {
+		Flags []struct {
+			Field   string `json:"field"`
+			Type    string `json:"type"`
+			Default any    `json:"default"`
+		} `json:"flags"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	got := make(map[string][2]any)
+	for _, f := range doc.Flags {
+		got[f.Field] = [2]any{f.Type, f.Default}
+	}
+
+	assert.Equal(t, [2]any{"string", "a,b"}, got["Include"])
+	assert.Equal(t, [2]any{"boolean", nil}, got["Verbose"])
+	assert.Equal(t, [2]any{"boolean", true}, got["Color"])
+	assert.Equal(t, [2]any{"string", nil}, got["Proxy"])
+}
@@ -0,0 +1,36 @@
+package argsieve
+
+import "errors"
+
+// ErrStop is returned by a [FlagHook] to stop parsing successfully. The
+// arguments after the one holding the flag are returned as positional,
+// even if they look like flags; the rest of a chain of short flags such
+// as "-hv" is ignored.
+var ErrStop = errors.New("argsieve: stop parsing")
+
+// FlagHook is called by [Sift] and [Parse] after each known flag is set,
+// for example to load a configuration file named by --config before later
+// flags override it, or to handle --help. Set it in [Config.Hook].
+type FlagHook interface {
+	// HandleFlag receives the flag's metadata and its raw value, which is
+	// empty for boolean flags. Returning [ErrStop] stops parsing; any other
+	// error aborts parsing and is returned unchanged.
+	HandleFlag(flag Flag, value string) error
+}
+
+// FlagHookFunc is an adapter to use an ordinary function as a [FlagHook].
+//
+// Example:
+//
+//	cfg := &argsieve.Config{Hook: argsieve.FlagHookFunc(func(flag argsieve.Flag, value string) error {
+//	    if flag.Long == "help" {
+//	        return argsieve.ErrStop
+//	    }
+//	    return nil
+//	})}
+type FlagHookFunc func(flag Flag, value string) error
+
+// HandleFlag calls f(flag, value).
+func (f FlagHookFunc) HandleFlag(flag Flag, value string) error {
+	return f(flag, value)
+}
@@ -0,0 +1,156 @@
+package argsieve
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookCall records one call to a FlagHook.
+type hookCall struct {
+	field string
+	value string
+}
+
+func TestConfig_Hook(t *testing.T) {
+	t.Parallel()
+
+	type hookFlags struct {
+		testFlags
+		Help  bool `short:"h" long:"help"`
+		Lines int  `numeric:"true"`
+	}
+
+	errBoom := errors.New("boom")
+
+	tests := map[string]struct {
+		args           []string
+		stopAt         string // field that stops parsing
+		failAt         string // field that fails
+		wantCalls      []hookCall
+		wantPositional []string
+		wantRemaining  []string
+		wantErr        error
+	}{
+		"called for each known flag": {
+			args: []string{"-v", "--region=eu", "-x", "-p", "dev", "-20", "file"},
+			wantCalls: []hookCall{
+				{field: "testFlags.Verbose"},
+				{field: "testFlags.Region", value: "eu"},
+				{field: "testFlags.Profile", value: "dev"},
+				{field: "Lines", value: "20"},
+			},
+			wantRemaining:  []string{"-x"},
+			wantPositional: []string{"file"},
+		},
+		"chained": {
+			args: []string{"-vdreu"},
+			wantCalls: []hookCall{
+				{field: "testFlags.Verbose"},
+				{field: "testFlags.Debug"},
+				{field: "testFlags.Region", value: "eu"},
+			},
+		},
+		"stop": {
+			args:           []string{"-x", "--help", "-v", "--", "a"},
+			stopAt:         "Help",
+			wantCalls:      []hookCall{{field: "Help"}},
+			wantRemaining:  []string{"-x"},
+			wantPositional: []string{"-v", "--", "a"},
+		},
+		"stop ignores rest of chain": {
+			args:           []string{"-hv", "a"},
+			stopAt:         "Help",
+			wantCalls:      []hookCall{{field: "Help"}},
+			wantPositional: []string{"a"},
+		},
+		"stop after value": {
+			args:           []string{"--region", "eu", "-v"},
+			stopAt:         "testFlags.Region",
+			wantCalls:      []hookCall{{field: "testFlags.Region", value: "eu"}},
+			wantPositional: []string{"-v"},
+		},
+		"error aborts": {
+			args:      []string{"-v", "-d", "-r", "eu"},
+			failAt:    "testFlags.Debug",
+			wantCalls: []hookCall{{field: "testFlags.Verbose"}, {field: "testFlags.Debug"}},
+			wantErr:   errBoom,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []hookCall
+
+			cfg := &Config{Hook: FlagHookFunc(func(flag Flag, value string) error {
+				calls = append(calls, hookCall{field: flag.Field, value: value})
+
+				switch flag.Field {
+				case tt.stopAt:
+					return ErrStop
+				case tt.failAt:
+					return errBoom
+				}
+
+				return nil
+			})}
+
+			var opts hookFlags
+			remaining, positional, err := Sift(&opts, tt.args, nil, cfg)
+
+			assert.Equal(t, tt.wantCalls, calls)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.NotErrorIs(t, err, ErrParse)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemaining, remaining)
+			assert.Equal(t, tt.wantPositional, positional)
+		})
+	}
+}
+
+func TestConfig_Hook_SeesEarlierFlags(t *testing.T) {
+	t.Parallel()
+
+	// A --config hook sets defaults that later flags override
+	var opts testFlags
+
+	cfg := &Config{Hook: FlagHookFunc(func(flag Flag, value string) error {
+		if flag.Long == "profile" {
+			opts.Region = "from-" + value
+			opts.Debug = true
+		}
+
+		return nil
+	})}
+
+	positional, err := Parse(&opts, []string{"--profile", "dev", "--region", "eu"}, cfg)
+
+	require.NoError(t, err)
+	assert.Empty(t, positional)
+	assert.Equal(t, testFlags{Profile: "dev", Region: "eu", Debug: true}, opts)
+}
+
+func TestConfig_Hook_StopInParse(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Hook: FlagHookFunc(func(Flag, string) error {
+		return ErrStop
+	})}
+
+	var opts testFlags
+	positional, err := Parse(&opts, []string{"-v", "--unknown"}, cfg)
+
+	require.NoError(t, err)
+	assert.True(t, opts.Verbose)
+	assert.Equal(t, []string{"--unknown"}, positional)
+}
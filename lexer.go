@@ -0,0 +1,187 @@
+package argsieve
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// TokenKind classifies a [Token].
+type TokenKind int
+
+const (
+	// TokenLong is a long flag (--name). Name holds the flag name without
+	// dashes; a value attached with "=" follows as a separate TokenValue.
+	TokenLong TokenKind = iota
+	// TokenShort is a single-dash argument (-x, -xyz, -xvalue). Name holds
+	// everything after the dash; how it splits into flags and an attached
+	// value depends on which flags take values, so it is left to the caller.
+	TokenShort
+	// TokenValue is a flag value: the text after "=" in --name=value, or
+	// an argument read with [Lexer.NextArg].
+	TokenValue
+	// TokenDelimiter is the "--" delimiter, or one set with
+	// [Lexer.Delimiters]. Every argument after it is a TokenPositional.
+	TokenDelimiter
+	// TokenPositional is a non-flag argument, including a lone "-".
+	TokenPositional
+)
+
+// String returns the name of the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenLong:
+		return "long"
+	case TokenShort:
+		return "short"
+	case TokenValue:
+		return "value"
+	case TokenDelimiter:
+		return "delimiter"
+	case TokenPositional:
+		return "positional"
+	default:
+		return "unknown"
+	}
+}
+
+// Token is a lexical element of a command line.
+type Token struct {
+	Kind TokenKind
+	// Name is the flag name without dashes for TokenLong and TokenShort.
+	Name string
+	// Value is the value of a TokenValue or the argument of a TokenPositional.
+	Value string
+	// Arg is the complete argument the token was read from.
+	Arg string
+	// Index is the index of Arg in the lexed arguments.
+	Index int
+}
+
+// Lexer splits command-line arguments into tokens using the same rules as
+// [Sift] and [Parse], without binding them to a struct. Parsing itself
+// consumes a Lexer, so the two always agree.
+//
+// The lexer does not know which flags take values. A caller that decides
+// a flag needs one reads it with [Lexer.NextArg], which takes the next
+// argument verbatim even if it looks like a flag or is "--".
+type Lexer struct {
+	args       []string
+	delimiters []string // nil means "--"
+	pos        int      // index of the next argument to read
+	pending    Token    // value attached to the last TokenLong
+	attach     bool     // true if pending has not been returned yet
+	delimited  bool     // true once a delimiter has been read
+}
+
+// NewLexer returns a Lexer over args.
+func NewLexer(args []string) *Lexer {
+	return &Lexer{args: args}
+}
+
+// Tokenize returns the tokens of args in order. For example,
+// ["-vx", "--out=a.txt", "--", "-f"] yields a TokenShort named "vx", a
+// TokenLong named "out", a TokenValue "a.txt", a TokenDelimiter and a
+// TokenPositional "-f".
+func Tokenize(args []string) iter.Seq[Token] {
+	return NewLexer(args).All()
+}
+
+// Delimiters sets the arguments that start the delimited section, such as
+// "-args" for go test or "--" for cargo run. The default is "--". An
+// argument "--" that is not a delimiter is a TokenPositional. Delimiters
+// must be called before the first call to Next.
+func (l *Lexer) Delimiters(delimiters ...string) {
+	l.delimiters = delimiters
+}
+
+// isDelimiter reports whether arg starts the delimited section.
+func (l *Lexer) isDelimiter(arg string) bool {
+	if l.delimiters == nil {
+		return arg == "--"
+	}
+
+	return slices.Contains(l.delimiters, arg)
+}
+
+// All returns the tokens not yet read from the lexer.
+func (l *Lexer) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for tok, ok := l.Next(); ok; tok, ok = l.Next() {
+			if !yield(tok) {
+				return
+			}
+		}
+	}
+}
+
+// Next returns the next token, or false when the arguments are exhausted.
+func (l *Lexer) Next() (Token, bool) {
+	if tok, ok := l.attached(); ok {
+		return tok, true
+	}
+
+	if l.pos >= len(l.args) {
+		return Token{}, false
+	}
+
+	index, arg := l.pos, l.args[l.pos]
+	l.pos++
+
+	switch {
+	case l.delimited:
+		return Token{Kind: TokenPositional, Value: arg, Arg: arg, Index: index}, true
+
+	case l.isDelimiter(arg):
+		l.delimited = true
+
+		return Token{Kind: TokenDelimiter, Arg: arg, Index: index}, true
+
+	case arg == "--":
+		return Token{Kind: TokenPositional, Value: arg, Arg: arg, Index: index}, true
+
+	case strings.HasPrefix(arg, "--"):
+		name, value, hasEquals := strings.Cut(arg[2:], "=")
+		if hasEquals {
+			l.pending = Token{Kind: TokenValue, Value: value, Arg: arg, Index: index}
+			l.attach = true
+		}
+
+		return Token{Kind: TokenLong, Name: name, Arg: arg, Index: index}, true
+
+	case strings.HasPrefix(arg, "-") && len(arg) > 1:
+		return Token{Kind: TokenShort, Name: arg[1:], Arg: arg, Index: index}, true
+
+	default:
+		return Token{Kind: TokenPositional, Value: arg, Arg: arg, Index: index}, true
+	}
+}
+
+// NextArg returns the next argument verbatim as a TokenValue, for a flag
+// that takes its value from the following argument. A value attached to
+// the previous long flag is discarded. Reading a delimiter this way does
+// not start the delimited section. Returns false when the arguments are
+// exhausted.
+func (l *Lexer) NextArg() (Token, bool) {
+	l.attach = false
+
+	if l.pos >= len(l.args) {
+		return Token{}, false
+	}
+
+	index, arg := l.pos, l.args[l.pos]
+	l.pos++
+
+	return Token{Kind: TokenValue, Value: arg, Arg: arg, Index: index}, true
+}
+
+// attached returns and consumes the value attached to the last TokenLong.
+func (l *Lexer) attached() (Token, bool) {
+	if !l.attach {
+		return Token{}, false
+	}
+
+	l.attach = false
+
+	return l.pending, true
+}
@@ -0,0 +1,152 @@
+package argsieve
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args []string
+		want []Token
+	}{
+		"empty": {
+			args: nil,
+			want: nil,
+		},
+		"long flags": {
+			args: []string{"--verbose", "--region=eu", "--empty=", "--a=b=c"},
+			want: []Token{
+				{Kind: TokenLong, Name: "verbose", Arg: "--verbose", Index: 0},
+				{Kind: TokenLong, Name: "region", Arg: "--region=eu", Index: 1},
+				{Kind: TokenValue, Value: "eu", Arg: "--region=eu", Index: 1},
+				{Kind: TokenLong, Name: "empty", Arg: "--empty=", Index: 2},
+				{Kind: TokenValue, Value: "", Arg: "--empty=", Index: 2},
+				{Kind: TokenLong, Name: "a", Arg: "--a=b=c", Index: 3},
+				{Kind: TokenValue, Value: "b=c", Arg: "--a=b=c", Index: 3},
+			},
+		},
+		"short clusters": {
+			args: []string{"-v", "-xvf", "-oval=x", "-é"},
+			want: []Token{
+				{Kind: TokenShort, Name: "v", Arg: "-v", Index: 0},
+				{Kind: TokenShort, Name: "xvf", Arg: "-xvf", Index: 1},
+				{Kind: TokenShort, Name: "oval=x", Arg: "-oval=x", Index: 2},
+				{Kind: TokenShort, Name: "é", Arg: "-é", Index: 3},
+			},
+		},
+		"positionals": {
+			args: []string{"file", "-", ""},
+			want: []Token{
+				{Kind: TokenPositional, Value: "file", Arg: "file", Index: 0},
+				{Kind: TokenPositional, Value: "-", Arg: "-", Index: 1},
+				{Kind: TokenPositional, Value: "", Arg: "", Index: 2},
+			},
+		},
+		"delimiter": {
+			args: []string{"-v", "--", "--region", "--", "-x"},
+			want: []Token{
+				{Kind: TokenShort, Name: "v", Arg: "-v", Index: 0},
+				{Kind: TokenDelimiter, Arg: "--", Index: 1},
+				{Kind: TokenPositional, Value: "--region", Arg: "--region", Index: 2},
+				{Kind: TokenPositional, Value: "--", Arg: "--", Index: 3},
+				{Kind: TokenPositional, Value: "-x", Arg: "-x", Index: 4},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := slices.Collect(Tokenize(tt.args))
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLexer_NextArg(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads flags and delimiter verbatim", func(t *testing.T) {
+		t.Parallel()
+
+		lex := NewLexer([]string{"--region", "--", "-v", "--", "x"})
+
+		tok, ok := lex.Next()
+		require.True(t, ok)
+		assert.Equal(t, TokenLong, tok.Kind)
+
+		// "--" consumed as a value does not start the delimited section
+		value, ok := lex.NextArg()
+		require.True(t, ok)
+		assert.Equal(t, Token{Kind: TokenValue, Value: "--", Arg: "--", Index: 1}, value)
+
+		got := slices.Collect(lex.All())
+		assert.Equal(t, []Token{
+			{Kind: TokenShort, Name: "v", Arg: "-v", Index: 2},
+			{Kind: TokenDelimiter, Arg: "--", Index: 3},
+			{Kind: TokenPositional, Value: "x", Arg: "x", Index: 4},
+		}, got)
+	})
+
+	t.Run("discards attached value", func(t *testing.T) {
+		t.Parallel()
+
+		lex := NewLexer([]string{"--a=1", "b"})
+
+		_, ok := lex.Next()
+		require.True(t, ok)
+
+		value, ok := lex.NextArg()
+		require.True(t, ok)
+		assert.Equal(t, "b", value.Value)
+
+		_, ok = lex.Next()
+		assert.False(t, ok)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := NewLexer(nil).NextArg()
+		assert.False(t, ok)
+	})
+}
+
+func TestLexer_All_StopsEarly(t *testing.T) {
+	t.Parallel()
+
+	lex := NewLexer([]string{"a", "b", "c"})
+
+	for tok := range lex.All() {
+		assert.Equal(t, "a", tok.Value)
+
+		break
+	}
+
+	tok, ok := lex.Next()
+	require.True(t, ok)
+	assert.Equal(t, "b", tok.Value)
+}
+
+func TestLexer_Delimiters(t *testing.T) {
+	t.Parallel()
+
+	lex := NewLexer([]string{"--", "-v", "-args", "-args", "-x"})
+	lex.Delimiters("-args")
+
+	assert.Equal(t, []Token{
+		{Kind: TokenPositional, Value: "--", Arg: "--", Index: 0},
+		{Kind: TokenShort, Name: "v", Arg: "-v", Index: 1},
+		{Kind: TokenDelimiter, Arg: "-args", Index: 2},
+		{Kind: TokenPositional, Value: "-args", Arg: "-args", Index: 3},
+		{Kind: TokenPositional, Value: "-x", Arg: "-x", Index: 4},
+	}, slices.Collect(lex.All()))
+}
@@ -0,0 +1,174 @@
+package argsieve
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Marshal is the inverse of [Parse]: it returns the arguments that set the
+// fields of target to their current values, so that parsing them into a
+// zero value with the same configuration reproduces target.
+//
+// Fields are emitted in declaration order, skipping zero values. Flags use
+// their long name when declared ("--name" for booleans, "--name=value"
+// otherwise, or "-name..." with [Config.SingleDashLong]) and their short
+// name otherwise ("-n" or "-n", "value"). A numeric field without names is
+// emitted as -<digits>. Values are not quoted; custom types, including
+// types parsed by a [ValueParser], must implement [encoding.TextMarshaler]
+// or [flag.Value]. A flag.Value field is emitted as its String form only
+// if setting that on a new value reproduces the field, so values that
+// accumulate over repeated flags, such as lists, need TextMarshaler.
+// Boolean flag.Value fields are emitted once, so a counter set more than
+// once cannot be represented.
+//
+// Example:
+//
+//	opts := Options{Region: "us-west-2", Verbose: true}
+//	args, err := argsieve.Marshal(&opts, nil)
+//	// args: ["--region=us-west-2", "--verbose"]
+//
+// Returns an error if a value cannot be represented: a custom type without
+// TextMarshaler, a flag.Value whose String form (or, for boolean flags, a
+// single Set("true")) does not reproduce it, a failing MarshalText, a
+// negative number for a numeric field without names, or a value of a
+// fromfile field that would be read as a file path.
+//
+// Panics if target is not a pointer to struct or if any tagged field
+// has an unsupported type.
+func Marshal(target any, cfg *Config) ([]string, error) {
+	return NewParser(target, cfg).Marshal(target)
+}
+
+// Marshal is like the package-level [Marshal] using the Parser's configuration.
+//
+// Panics if target is not a non-nil pointer to the Parser's struct type.
+func (p *Parser) Marshal(target any) ([]string, error) {
+	s := p.newSieve(target, true)
+
+	var args []string
+
+	for _, spec := range s.schema.fields {
+		info := s.bind(spec)
+		if info.field.IsZero() {
+			continue
+		}
+
+		value, err := formatField(info)
+		if err != nil {
+			return nil, fmt.Errorf("argsieve: field %s: %w", spec.name, err)
+		}
+
+		if spec.fromFile && (strings.HasPrefix(value, "@") || strings.HasPrefix(value, "file:")) {
+			return nil, fmt.Errorf("argsieve: field %s: value %q would be read as a file", spec.name, value)
+		}
+
+		switch {
+		case spec.long != "":
+			flag := "--" + spec.long
+			if p.cfg.SingleDashLong {
+				flag = "-" + spec.long
+			}
+			if spec.needsArg {
+				flag += "=" + value
+			}
+			args = append(args, flag)
+		case spec.short != "":
+			args = append(args, "-"+spec.short)
+			if spec.needsArg {
+				args = append(args, value)
+			}
+		default:
+			// Numeric field with no names
+			if !isNumericShorthand("-" + value) {
+				return nil, fmt.Errorf("argsieve: field %s: value %s has no -<digits> form", spec.name, value)
+			}
+			args = append(args, "-"+value)
+		}
+	}
+
+	return args, nil
+}
+
+// formatField returns the text form of a non-zero field value, as it
+// would be passed to setField.
+func formatField(info fieldInfo) (string, error) {
+	field := info.field
+
+	if text, ok, err := marshalText(field); ok {
+		return text, err
+	}
+
+	if info.value {
+		if !info.needsArg {
+			if !setsBack(field, "true") {
+				return "", fmt.Errorf("boolean flag type %s: Set(\"true\") does not reproduce the value", field.Type())
+			}
+
+			return "", nil
+		}
+
+		text := flagValue(field).String()
+		if !setsBack(field, text) {
+			return "", fmt.Errorf("type %s does not implement encoding.TextMarshaler and Set(%q) does not reproduce the value", field.Type(), text)
+		}
+
+		return text, nil
+	}
+
+	if info.isPtr || info.unsupported != "" || reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		return "", fmt.Errorf("type %s does not implement encoding.TextMarshaler", field.Type())
+	}
+
+	switch kind := field.Kind(); {
+	case kind == reflect.Bool:
+		return "", nil
+	case isIntKind(kind) && field.CanInt():
+		return strconv.FormatInt(field.Int(), 10), nil
+	case isIntKind(kind):
+		return strconv.FormatUint(field.Uint(), 10), nil
+	default:
+		return field.String(), nil
+	}
+}
+
+// setsBack reports whether calling Set with text on a new flag.Value of
+// the field's type reproduces the field's value. Values that accumulate,
+// such as lists, do not survive being joined into a single String.
+func setsBack(field reflect.Value, text string) bool {
+	if field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+
+	v := reflect.New(field.Type())
+	if err := v.Interface().(flag.Value).Set(text); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(v.Elem().Interface(), field.Interface())
+}
+
+// marshalText returns the text form of an addressable, non-nil field
+// value whose type, or the type it points to, implements
+// encoding.TextMarshaler. Reports false if neither does.
+func marshalText(field reflect.Value) (text string, ok bool, err error) {
+	var tm encoding.TextMarshaler
+
+	switch {
+	case field.Kind() == reflect.Ptr:
+		tm, ok = field.Interface().(encoding.TextMarshaler)
+	case reflect.PointerTo(field.Type()).Implements(textMarshalerType):
+		tm, ok = field.Addr().Interface().(encoding.TextMarshaler)
+	}
+
+	if !ok {
+		return "", false, nil
+	}
+
+	data, err := tm.MarshalText()
+
+	return string(data), true, err
+}
@@ -0,0 +1,153 @@
+package argsieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type marshalFlags struct {
+	testEmbeddedBase
+	Profile string       `short:"p"`
+	Lines   int          `short:"n" numeric:"true"`
+	Level   exportLevel  `long:"level"`
+	Ptr     *exportLevel `long:"ptr"`
+	Token   string       `long:"token" fromfile:"true"`
+	Verbose bool         `short:"v" long:"verbose"`
+	Debug   bool         `short:"d"`
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts marshalFlags
+		cfg  *Config
+		want []string
+	}{
+		"zero value": {},
+		"all fields": {
+			opts: marshalFlags{
+				testEmbeddedBase: testEmbeddedBase{Region: "us-west-2"},
+				Profile:          "-dash value",
+				Lines:            -3,
+				Level:            "info",
+				Ptr:              ptrTo(exportLevel("a=b")),
+				Token:            "plain",
+				Verbose:          true,
+				Debug:            true,
+			},
+			want: []string{
+				"--region=us-west-2",
+				"-p", "-dash value",
+				"-n", "-3",
+				"--level=info",
+				"--ptr=a=b",
+				"--token=plain",
+				"--verbose",
+				"-d",
+			},
+		},
+		"single dash long": {
+			opts: marshalFlags{testEmbeddedBase: testEmbeddedBase{Region: "eu"}, Verbose: true},
+			cfg:  &Config{SingleDashLong: true},
+			want: []string{"-region=eu", "-verbose"},
+		},
+		"empty pointer value": {
+			opts: marshalFlags{Ptr: ptrTo(exportLevel(""))},
+			want: []string{"--ptr="},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			args, err := Marshal(&tc.opts, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, args)
+
+			// Parse(Marshal(x)) round-trips
+			var parsed marshalFlags
+			positional, err := Parse(&parsed, args, tc.cfg)
+			require.NoError(t, err)
+			assert.Empty(t, positional)
+			assert.Equal(t, tc.opts, parsed)
+		})
+	}
+}
+
+func TestMarshal_NumericWithoutNames(t *testing.T) {
+	t.Parallel()
+
+	type options struct {
+		Count uint8 `numeric:"true"`
+	}
+
+	args, err := Marshal(&options{Count: 9}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-9"}, args)
+
+	var parsed options
+	_, err = Parse(&parsed, args, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(9), parsed.Count)
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	t.Parallel()
+
+	type noMarshaler struct {
+		Level logLevel `long:"level"`
+	}
+
+	type negativeNumeric struct {
+		Count int `numeric:"true"`
+	}
+
+	tests := map[string]struct {
+		target      any
+		errContains string
+	}{
+		"type without TextMarshaler": {
+			target:      &noMarshaler{Level: logLevelDebug},
+			errContains: "does not implement encoding.TextMarshaler",
+		},
+		"failing MarshalText": {
+			target:      &marshalFlags{Level: "broken"},
+			errContains: "cannot marshal",
+		},
+		"fromfile value looks like a file": {
+			target:      &marshalFlags{Token: "@secret"},
+			errContains: "would be read as a file",
+		},
+		"negative numeric without names": {
+			target:      &negativeNumeric{Count: -1},
+			errContains: "has no -<digits> form",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Marshal(tc.target, nil)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+func TestMarshal_PanicsOnInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		_, _ = Marshal(testFlags{}, nil)
+	})
+
+	assert.Panics(t, func() {
+		_, _ = Marshal((*testFlags)(nil), nil)
+	})
+}
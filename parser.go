@@ -0,0 +1,123 @@
+package argsieve
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+)
+
+// Parser parses arguments into values of a single options struct type.
+//
+// NewParser extracts the struct's flag layout once; each call to
+// [Parser.Sift] or [Parser.Parse] only binds it to the given target.
+// Use a Parser when parsing many argument lists for the same type.
+// A Parser is safe for concurrent use by multiple goroutines, provided
+// each call is given its own target.
+type Parser struct {
+	schema *schema
+	cfg    Config
+}
+
+// NewParser returns a Parser for the struct type that target points to.
+// Only the type of target is used, so a nil pointer such as
+// (*Options)(nil) is accepted.
+//
+// The cfg parameter allows optional configuration. Pass nil to use
+// defaults. The configuration is copied; later changes to cfg have no
+// effect on the Parser.
+//
+// Example:
+//
+//	p := argsieve.NewParser((*Options)(nil), nil)
+//	for _, args := range requests {
+//	    var opts Options
+//	    remaining, positional, err := p.Sift(&opts, args, []string{"-o"})
+//	    // ...
+//	}
+//
+// Panics if target is not a pointer to struct or if any tagged field
+// has an unsupported type.
+func NewParser(target any, cfg *Config) *Parser {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("argsieve: target must be a pointer to struct, got %T", target))
+	}
+
+	p, err := newParser(t.Elem(), cfg)
+	if err != nil {
+		panic("argsieve: " + err.Error())
+	}
+
+	return p
+}
+
+// newParser returns a Parser for struct type t, or an error if t has
+// an invalid schema.
+func newParser(t reflect.Type, cfg *Config) (*Parser, error) {
+	sc, err := loadSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Parser{schema: sc}
+
+	if cfg != nil {
+		p.cfg = *cfg
+		p.cfg.Passthrough = cfg.Passthrough.clone()
+		p.cfg.TypeParsers = maps.Clone(cfg.TypeParsers)
+		p.cfg.FieldParsers = maps.Clone(cfg.FieldParsers)
+		p.cfg.Delimiters = slices.Clone(cfg.Delimiters)
+	}
+
+	if p.cfg.FS == nil {
+		p.cfg.FS = osFS{}
+	}
+
+	if p.cfg.Stdin == nil {
+		p.cfg.Stdin = os.Stdin
+	}
+
+	if err := sc.check(&p.cfg); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Sift is like the package-level [Sift] using the Parser's configuration.
+//
+// Panics if target is not a non-nil pointer to the Parser's struct type.
+func (p *Parser) Sift(target any, args []string, passthroughWithArg []string) (remaining, positional []string, err error) {
+	s := p.newSieve(target, false)
+	s.passthrough = passthroughWithArg
+
+	return s.parse(args)
+}
+
+// Parse is like the package-level [Parse] using the Parser's configuration.
+//
+// Panics if target is not a non-nil pointer to the Parser's struct type.
+func (p *Parser) Parse(target any, args []string) (positional []string, err error) {
+	s := p.newSieve(target, true)
+
+	_, positional, err = s.parse(args)
+
+	return positional, err
+}
+
+// newSieve creates a sieve that binds the Parser's schema to target.
+func (p *Parser) newSieve(target any, strict bool) *sieve {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Type() != p.schema.typ {
+		panic(fmt.Sprintf("argsieve: target must be a non-nil *%s, got %T", p.schema.typ, target))
+	}
+
+	return &sieve{
+		schema: p.schema,
+		cfg:    &p.cfg,
+		target: v.Elem(),
+		strict: strict,
+	}
+}
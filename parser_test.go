@@ -0,0 +1,194 @@
+package argsieve
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_Reuse(t *testing.T) {
+	t.Parallel()
+
+	p := NewParser((*testFlags)(nil), nil)
+
+	var first testFlags
+	remaining, positional, err := p.Sift(&first, []string{"-v", "-x", "host1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-x"}, remaining)
+	assert.Equal(t, []string{"host1"}, positional)
+	assert.True(t, first.Verbose)
+
+	var second testFlags
+	positional, err = p.Parse(&second, []string{"--region", "us-west-2", "host2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"host2"}, positional)
+	assert.Equal(t, "us-west-2", second.Region)
+	assert.False(t, second.Verbose)
+}
+
+func TestParser_EmbeddedStruct(t *testing.T) {
+	t.Parallel()
+
+	p := NewParser(&testEmbedded{}, nil)
+
+	var flags testEmbedded
+	_, err := p.Parse(&flags, []string{"-r", "us-west-2", "-p", "myprofile"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", flags.Region)
+	assert.Equal(t, "myprofile", flags.Profile)
+}
+
+func TestParser_ConfigCopied(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{StopAtFirstPositional: true}
+	p := NewParser((*testFlags)(nil), cfg)
+	cfg.StopAtFirstPositional = false
+
+	var flags testFlags
+	positional, err := p.Parse(&flags, []string{"file", "-v"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file", "-v"}, positional)
+	assert.False(t, flags.Verbose)
+}
+
+func TestParser_DelimitersCopied(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Delimiters: []string{"::"}}
+	p := NewParser((*testFlags)(nil), cfg)
+	cfg.Delimiters[0] = "--"
+
+	var flags testFlags
+	positional, err := p.Parse(&flags, []string{"::", "-v"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-v"}, positional)
+	assert.False(t, flags.Verbose)
+}
+
+func TestParser_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	p := NewParser((*testFlags)(nil), nil)
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			region := fmt.Sprintf("region-%d", i)
+			var flags testFlags
+			_, _, err := p.Sift(&flags, []string{"-v", "--region", region, "-x"}, nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, region, flags.Region)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoadSchema_Cached(t *testing.T) {
+	t.Parallel()
+
+	typ := reflect.TypeFor[testFlags]()
+
+	first, err := loadSchema(typ)
+	require.NoError(t, err)
+
+	second, err := loadSchema(typ)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+}
+
+func TestNewParser_Panics(t *testing.T) {
+	t.Parallel()
+
+	type badStruct struct {
+		Count float64 `short:"c"`
+	}
+
+	tests := map[string]struct {
+		target any
+	}{
+		"nil target":       {target: nil},
+		"non-pointer":      {target: testFlags{}},
+		"pointer to int":   {target: new(int)},
+		"unsupported type": {target: (*badStruct)(nil)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Panics(t, func() {
+				NewParser(tc.target, nil)
+			})
+		})
+	}
+}
+
+func TestParser_PanicsOnWrongTarget(t *testing.T) {
+	t.Parallel()
+
+	p := NewParser((*testFlags)(nil), nil)
+
+	tests := map[string]struct {
+		target any
+	}{
+		"nil pointer":    {target: (*testFlags)(nil)},
+		"non-pointer":    {target: testFlags{}},
+		"different type": {target: &testEmbedded{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Panics(t, func() {
+				_, _ = p.Parse(tc.target, nil)
+			})
+		})
+	}
+}
+
+var benchArgs = []string{"-v", "--region", "us-west-2", "-o", "StrictHostKeyChecking=no", "-x", "host", "uptime"}
+
+func BenchmarkSift(b *testing.B) {
+	b.ReportAllocs()
+
+	for range b.N {
+		var flags testFlags
+		_, _, _ = Sift(&flags, benchArgs, []string{"-o"}, nil)
+	}
+}
+
+func BenchmarkParser_Sift(b *testing.B) {
+	p := NewParser((*testFlags)(nil), nil)
+	b.ReportAllocs()
+
+	for range b.N {
+		var flags testFlags
+		_, _, _ = p.Sift(&flags, benchArgs, []string{"-o"})
+	}
+}
+
+// BenchmarkSift_Uncached compiles the schema on every call, as Sift did
+// before schemas were cached.
+func BenchmarkSift_Uncached(b *testing.B) {
+	typ := reflect.TypeFor[testFlags]()
+	b.ReportAllocs()
+
+	for range b.N {
+		sc, _ := compileSchema(typ)
+		p := &Parser{schema: sc}
+		var flags testFlags
+		_, _, _ = p.Sift(&flags, benchArgs, []string{"-o"})
+	}
+}
@@ -0,0 +1,300 @@
+package argsieve
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ArgRequirement tells whether a passthrough flag takes a value, like the
+// has_arg field of getopt_long(3).
+type ArgRequirement int
+
+const (
+	// NoArgument marks a flag that takes no value.
+	NoArgument ArgRequirement = iota
+	// RequiredArgument marks a flag that takes a value, either attached
+	// (--name=value, -nvalue) or in the next argument.
+	RequiredArgument
+	// OptionalArgument marks a flag that takes a value only when it is
+	// attached (--name=value, -nvalue). The next argument is never consumed.
+	OptionalArgument
+)
+
+// LongOption describes a long option of a wrapped tool, like struct option
+// in getopt_long(3). Name is given without dashes.
+type LongOption struct {
+	Name   string
+	HasArg ArgRequirement
+}
+
+// PassthroughSpec describes the flags of the command [Sift] forwards
+// unknown flags to, so that their values are forwarded with them. Set it
+// in [Config.Passthrough]; it extends the passthroughWithArg list. With
+// [Config.StrictPassthrough] it is the complete flag table of the wrapped
+// command.
+//
+// The zero value is an empty spec ready to use.
+type PassthroughSpec struct {
+	flags map[string]passthroughFlag // keyed by prefixed name (-x, --name)
+}
+
+// passthroughFlag describes how many values a passthrough flag takes.
+type passthroughFlag struct {
+	hasArg ArgRequirement
+	arity  int // number of values of a flag with a required argument
+}
+
+// newPassthroughFlag returns a flag that takes a single value as hasArg says.
+func newPassthroughFlag(hasArg ArgRequirement) passthroughFlag {
+	if hasArg == RequiredArgument {
+		return passthroughFlag{hasArg: hasArg, arity: 1}
+	}
+
+	return passthroughFlag{hasArg: hasArg}
+}
+
+// AddOptstring adds the short options described by a getopt(3) optstring.
+// Each option character is followed by ":" if it requires an argument or
+// "::" if it takes an optional one. Leading "+", "-" and ":" modifiers
+// are ignored.
+//
+// Example:
+//
+//	var spec argsieve.PassthroughSpec
+//	err := spec.AddOptstring("46AaCfGgKkMNnqsTtVvXxYyB:b:c:D:E:e:F:I:i:J:L:l:m:O:o:p:Q:R:S:W:w:")
+//
+// Returns an error if the optstring contains an invalid option character.
+// No options are added in that case.
+func (p *PassthroughSpec) AddOptstring(optstring string) error {
+	rest := strings.TrimPrefix(strings.TrimLeft(optstring, "+-"), ":")
+	parsed := make(map[string]ArgRequirement)
+
+	for rest != "" {
+		r, size := utf8.DecodeRuneInString(rest)
+		if r == ':' || r == '-' || r == utf8.RuneError || unicode.IsSpace(r) {
+			return fmt.Errorf("argsieve: invalid option character %q in optstring %q", rest[:size], optstring)
+		}
+
+		name := rest[:size]
+		rest = rest[size:]
+
+		hasArg := NoArgument
+
+		switch {
+		case strings.HasPrefix(rest, "::"):
+			hasArg = OptionalArgument
+			rest = rest[2:]
+		case strings.HasPrefix(rest, ":"):
+			hasArg = RequiredArgument
+			rest = rest[1:]
+		}
+
+		parsed["-"+name] = hasArg
+	}
+
+	for flag, hasArg := range parsed {
+		p.set(flag, newPassthroughFlag(hasArg))
+	}
+
+	return nil
+}
+
+// AddLongOptions adds long options, as described by a getopt_long(3)
+// option table.
+//
+// Example:
+//
+//	err := spec.AddLongOptions(
+//	    argsieve.LongOption{Name: "color", HasArg: argsieve.OptionalArgument},
+//	    argsieve.LongOption{Name: "file", HasArg: argsieve.RequiredArgument},
+//	)
+//
+// Returns an error if a name is empty, starts with "-" or contains "=", or
+// if HasArg is not one of the ArgRequirement constants. No options are
+// added in that case.
+func (p *PassthroughSpec) AddLongOptions(options ...LongOption) error {
+	for _, opt := range options {
+		if opt.Name == "" || strings.HasPrefix(opt.Name, "-") || strings.Contains(opt.Name, "=") {
+			return fmt.Errorf("argsieve: invalid long option name %q", opt.Name)
+		}
+
+		if !opt.HasArg.valid() {
+			return fmt.Errorf("argsieve: invalid argument requirement %d for long option %q", opt.HasArg, opt.Name)
+		}
+	}
+
+	for _, opt := range options {
+		p.set("--"+opt.Name, newPassthroughFlag(opt.HasArg))
+	}
+
+	return nil
+}
+
+// AddFlags adds flags given by their full names, such as "-v", "--all" or
+// "-name" for tools that use single-dash long flags (see
+// [Config.SingleDashLong]), that all take a value as hasArg says.
+//
+// Example:
+//
+//	err := spec.AddFlags(argsieve.NoArgument, "-v", "--verbose")
+//	err = spec.AddFlags(argsieve.RequiredArgument, "-o", "--output")
+//
+// Returns an error if a name does not start with "-", is "-" or "--",
+// contains "=", or if hasArg is not one of the ArgRequirement constants.
+// No flags are added in that case.
+func (p *PassthroughSpec) AddFlags(hasArg ArgRequirement, names ...string) error {
+	if !hasArg.valid() {
+		return fmt.Errorf("argsieve: invalid argument requirement %d", hasArg)
+	}
+
+	return p.addFlags(newPassthroughFlag(hasArg), names)
+}
+
+// AddFlagsArity adds flags given by their full names, as in
+// [PassthroughSpec.AddFlags], that each take exactly arity values from the
+// arguments that follow, such as "--pos X Y". A value attached to the flag
+// (--name=value, -nvalue) counts as the first one. An arity of 0 declares
+// flags that take no value.
+//
+// Example:
+//
+//	err := spec.AddFlagsArity(2, "--scale-from")
+//	// "--scale-from 800 600 out" → remaining ["--scale-from", "800", "600"]
+//
+// If fewer values are left, the available ones are forwarded unless
+// [Config.RequirePassthroughValues] is set.
+//
+// Returns an error if arity is negative or a name is invalid, as for
+// AddFlags. No flags are added in that case.
+func (p *PassthroughSpec) AddFlagsArity(arity int, names ...string) error {
+	switch {
+	case arity < 0:
+		return fmt.Errorf("argsieve: invalid arity %d", arity)
+	case arity == 0:
+		return p.addFlags(passthroughFlag{hasArg: NoArgument}, names)
+	default:
+		return p.addFlags(passthroughFlag{hasArg: RequiredArgument, arity: arity}, names)
+	}
+}
+
+// addFlags adds flags given by their full names.
+func (p *PassthroughSpec) addFlags(flag passthroughFlag, names []string) error {
+	for _, name := range names {
+		if !strings.HasPrefix(name, "-") || name == "-" || name == "--" || strings.Contains(name, "=") {
+			return fmt.Errorf("argsieve: invalid flag name %q", name)
+		}
+	}
+
+	for _, name := range names {
+		p.set(name, flag)
+	}
+
+	return nil
+}
+
+// valid reports whether r is one of the ArgRequirement constants.
+func (r ArgRequirement) valid() bool {
+	return r >= NoArgument && r <= OptionalArgument
+}
+
+// set records the values taken by the flag with a prefixed name.
+func (p *PassthroughSpec) set(name string, flag passthroughFlag) {
+	if p.flags == nil {
+		p.flags = make(map[string]passthroughFlag)
+	}
+
+	p.flags[name] = flag
+}
+
+// clone returns a copy of p that is not affected by later changes to p.
+func (p *PassthroughSpec) clone() *PassthroughSpec {
+	if p == nil {
+		return nil
+	}
+
+	return &PassthroughSpec{flags: maps.Clone(p.flags)}
+}
+
+// lookup returns the values taken by the flag with a prefixed name, and
+// false if it is not declared. p may be nil.
+func (p *PassthroughSpec) lookup(name string) (passthroughFlag, bool) {
+	if p == nil {
+		return passthroughFlag{}, false
+	}
+
+	flag, ok := p.flags[name]
+
+	return flag, ok
+}
+
+// passthroughArg returns the values taken by an unknown flag, according
+// to the passthroughWithArg list and [Config.Passthrough], and false if
+// it is declared in neither.
+func (s *sieve) passthroughArg(flag string) (passthroughFlag, bool) {
+	if slices.Contains(s.passthrough, flag) {
+		return newPassthroughFlag(RequiredArgument), true
+	}
+
+	return s.cfg.Passthrough.lookup(flag)
+}
+
+// passValues forwards the next n arguments to remaining as values of an
+// unknown flag. Running out of arguments is an error only with
+// [Config.RequirePassthroughValues].
+func (s *sieve) passValues(flag string, n int, next func() (string, bool)) error {
+	for range n {
+		value, ok := next()
+		if !ok {
+			if s.cfg.RequirePassthroughValues {
+				return fmt.Errorf("%w: missing value for %s", ErrParse, flag)
+			}
+
+			return nil
+		}
+
+		s.addRemainingValue(value)
+	}
+
+	return nil
+}
+
+// UnknownOptionError reports a flag that is not declared in the options
+// struct in [Parse], or in neither the options struct nor the passthrough
+// specification in [Sift] with [Config.StrictPassthrough].
+//
+// It matches [ErrParse] with [errors.Is]; use [errors.As] to get the flag:
+//
+//	var unknown *argsieve.UnknownOptionError
+//	if errors.As(err, &unknown) {
+//	    fmt.Println("did you mean:", suggest(unknown.Option))
+//	}
+type UnknownOptionError struct {
+	// Option is the flag as written, without any attached value
+	// (-x, --name).
+	Option string
+}
+
+// Error returns the error message.
+func (e *UnknownOptionError) Error() string {
+	return fmt.Sprintf("%v: unknown option %s", ErrParse, e.Option)
+}
+
+// Unwrap returns [ErrParse].
+func (e *UnknownOptionError) Unwrap() error {
+	return ErrParse
+}
+
+// unknownOption returns an error for an unknown flag if unknown flags are
+// rejected: always in strict mode and, with [Config.StrictPassthrough],
+// unless the passthrough specification declares the flag.
+func (s *sieve) unknownOption(flag string, declared bool) error {
+	if s.strict || (s.cfg.StrictPassthrough && !declared) {
+		return &UnknownOptionError{Option: flag}
+	}
+
+	return nil
+}
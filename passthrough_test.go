@@ -0,0 +1,550 @@
+package argsieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sshOptstring is the optstring from the ssh(1) man page.
+const sshOptstring = "46AaCfGgKkMNnqsTtVvXxYyB:b:c:D:E:e:F:I:i:J:L:l:m:O:o:p:Q:R:S:W:w:"
+
+// requirements returns the argument requirement of each flag in spec.
+func requirements(spec *PassthroughSpec) map[string]ArgRequirement {
+	if spec.flags == nil {
+		return nil
+	}
+
+	reqs := make(map[string]ArgRequirement, len(spec.flags))
+	for name, flag := range spec.flags {
+		reqs[name] = flag.hasArg
+	}
+
+	return reqs
+}
+
+func TestPassthroughSpec_AddOptstring(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		optstring string
+		want      map[string]ArgRequirement
+		wantErr   string
+	}{
+		"flags and arguments": {
+			optstring: "ab:c::",
+			want:      map[string]ArgRequirement{"-a": NoArgument, "-b": RequiredArgument, "-c": OptionalArgument},
+		},
+		"leading modifiers": {
+			optstring: "+:x:",
+			want:      map[string]ArgRequirement{"-x": RequiredArgument},
+		},
+		"unicode": {
+			optstring: "é:",
+			want:      map[string]ArgRequirement{"-é": RequiredArgument},
+		},
+		"empty": {
+			optstring: "",
+			want:      nil,
+		},
+		"too many colons": {
+			optstring: "a:::",
+			wantErr:   `invalid option character ":"`,
+		},
+		"dash": {
+			optstring: "a-b",
+			wantErr:   `invalid option character "-"`,
+		},
+		"space": {
+			optstring: "a b",
+			wantErr:   `invalid option character " "`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var spec PassthroughSpec
+			err := spec.AddOptstring(tt.optstring)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				assert.Empty(t, spec.flags)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, requirements(&spec))
+		})
+	}
+}
+
+func TestPassthroughSpec_AddLongOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		var spec PassthroughSpec
+		err := spec.AddLongOptions(
+			LongOption{Name: "all"},
+			LongOption{Name: "file", HasArg: RequiredArgument},
+			LongOption{Name: "color", HasArg: OptionalArgument},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]ArgRequirement{
+			"--all":   NoArgument,
+			"--file":  RequiredArgument,
+			"--color": OptionalArgument,
+		}, requirements(&spec))
+	})
+
+	tests := map[string]struct {
+		option  LongOption
+		wantErr string
+	}{
+		"empty name":      {option: LongOption{Name: ""}, wantErr: `invalid long option name ""`},
+		"dashes":          {option: LongOption{Name: "--file"}, wantErr: `invalid long option name "--file"`},
+		"equals":          {option: LongOption{Name: "a=b"}, wantErr: `invalid long option name "a=b"`},
+		"bad requirement": {option: LongOption{Name: "file", HasArg: 7}, wantErr: "invalid argument requirement 7"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var spec PassthroughSpec
+			err := spec.AddLongOptions(LongOption{Name: "ok"}, tt.option)
+
+			require.ErrorContains(t, err, tt.wantErr)
+			assert.Empty(t, spec.flags)
+		})
+	}
+}
+
+func TestSift_PassthroughSpec(t *testing.T) {
+	t.Parallel()
+
+	type wrapperFlags struct {
+		Debug bool `short:"d" long:"debug"`
+	}
+
+	var spec PassthroughSpec
+	require.NoError(t, spec.AddOptstring(sshOptstring+"X::"))
+	require.NoError(t, spec.AddLongOptions(
+		LongOption{Name: "file", HasArg: RequiredArgument},
+		LongOption{Name: "color", HasArg: OptionalArgument},
+	))
+
+	tests := map[string]struct {
+		args               []string
+		passthroughWithArg []string
+		cfg                Config
+		wantRemaining      []string
+		wantPositional     []string
+	}{
+		"ssh command line": {
+			args:           []string{"-d", "-p", "2222", "-L8080:localhost:80", "-tt", "-i", "key", "host", "uptime"},
+			wantRemaining:  []string{"-p", "2222", "-L8080:localhost:80", "-t", "-t", "-i", "key"},
+			wantPositional: []string{"host", "uptime"},
+		},
+		"chained with required argument": {
+			args:          []string{"-Ao", "BatchMode=yes"},
+			wantRemaining: []string{"-A", "-o", "BatchMode=yes"},
+		},
+		"optional short argument attached": {
+			args:           []string{"-Xfoo", "bar"},
+			wantRemaining:  []string{"-Xfoo"},
+			wantPositional: []string{"bar"},
+		},
+		"optional short argument not consumed": {
+			args:           []string{"-X", "bar"},
+			wantRemaining:  []string{"-X"},
+			wantPositional: []string{"bar"},
+		},
+		"required long argument": {
+			args:          []string{"--file", "a.txt", "--file=b.txt"},
+			wantRemaining: []string{"--file", "a.txt", "--file=b.txt"},
+		},
+		"optional long argument": {
+			args:           []string{"--color", "auto", "--color=always"},
+			wantRemaining:  []string{"--color", "--color=always"},
+			wantPositional: []string{"auto"},
+		},
+		"merged with passthroughWithArg": {
+			args:               []string{"-Z", "z", "--color", "c"},
+			passthroughWithArg: []string{"-Z", "--color"},
+			wantRemaining:      []string{"-Z", "z", "--color", "c"},
+		},
+		"single dash long": {
+			args:           []string{"-hello", "a.txt"},
+			cfg:            Config{SingleDashLong: true},
+			wantRemaining:  []string{"-hello"},
+			wantPositional: []string{"a.txt"},
+		},
+		"single dash long with declared short": {
+			args:           []string{"-file", "a.txt"},
+			cfg:            Config{SingleDashLong: true},
+			wantRemaining:  []string{"-f", "-ile"},
+			wantPositional: []string{"a.txt"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Passthrough = &spec
+
+			var opts wrapperFlags
+			remaining, positional, err := Sift(&opts, tt.args, tt.passthroughWithArg, &cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemaining, remaining)
+			assert.Equal(t, tt.wantPositional, positional)
+		})
+	}
+}
+
+func TestParser_PassthroughSpecCopied(t *testing.T) {
+	t.Parallel()
+
+	var spec PassthroughSpec
+	p := NewParser((*testFlags)(nil), &Config{Passthrough: &spec})
+
+	require.NoError(t, spec.AddOptstring("Z:"))
+
+	var opts testFlags
+	remaining, positional, err := p.Sift(&opts, []string{"-Z", "z"}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-Z"}, remaining)
+	assert.Equal(t, []string{"z"}, positional)
+}
+
+func TestPassthroughSpec_AddFlags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		var spec PassthroughSpec
+		require.NoError(t, spec.AddFlags(NoArgument, "-v", "--verbose"))
+		require.NoError(t, spec.AddFlags(RequiredArgument, "-o", "-name"))
+
+		assert.Equal(t, map[string]ArgRequirement{
+			"-v":        NoArgument,
+			"--verbose": NoArgument,
+			"-o":        RequiredArgument,
+			"-name":     RequiredArgument,
+		}, requirements(&spec))
+	})
+
+	tests := map[string]struct {
+		hasArg  ArgRequirement
+		name    string
+		wantErr string
+	}{
+		"no dash":         {name: "v", wantErr: `invalid flag name "v"`},
+		"single dash":     {name: "-", wantErr: `invalid flag name "-"`},
+		"delimiter":       {name: "--", wantErr: `invalid flag name "--"`},
+		"equals":          {name: "--a=b", wantErr: `invalid flag name "--a=b"`},
+		"bad requirement": {hasArg: -1, name: "-v", wantErr: "invalid argument requirement -1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var spec PassthroughSpec
+			err := spec.AddFlags(tt.hasArg, "-ok", tt.name)
+
+			require.ErrorContains(t, err, tt.wantErr)
+			assert.Empty(t, spec.flags)
+		})
+	}
+}
+
+func TestSift_StrictPassthrough(t *testing.T) {
+	t.Parallel()
+
+	var spec PassthroughSpec
+	require.NoError(t, spec.AddOptstring("ab:"))
+	require.NoError(t, spec.AddFlags(NoArgument, "--all"))
+	require.NoError(t, spec.AddFlags(RequiredArgument, "-name"))
+
+	tests := map[string]struct {
+		args               []string
+		passthroughWithArg []string
+		cfg                Config
+		wantRemaining      []string
+		wantPositional     []string
+		wantUnknown        string
+	}{
+		"declared flags": {
+			args:           []string{"-ab", "x", "--all", "-r", "eu", "file"},
+			wantRemaining:  []string{"-a", "-b", "x", "--all"},
+			wantPositional: []string{"file"},
+		},
+		"passthroughWithArg is declared": {
+			args:               []string{"--output", "out"},
+			wantRemaining:      []string{"--output", "out"},
+			passthroughWithArg: []string{"--output"},
+		},
+		"unknown long": {
+			args:        []string{"--al"},
+			wantUnknown: "--al",
+		},
+		"unknown long with value": {
+			args:        []string{"--all", "--colour=auto"},
+			wantUnknown: "--colour",
+		},
+		"unknown short in chain": {
+			args:        []string{"-az"},
+			wantUnknown: "-z",
+		},
+		"single dash long declared": {
+			args:          []string{"-name", "x"},
+			cfg:           Config{SingleDashLong: true},
+			wantRemaining: []string{"-name", "x"},
+		},
+		"single dash long unknown": {
+			args:        []string{"-nmae", "x"},
+			cfg:         Config{SingleDashLong: true},
+			wantUnknown: "-nmae",
+		},
+		"after delimiter": {
+			args:           []string{"--", "--verbsoe"},
+			wantPositional: []string{"--verbsoe"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Passthrough = &spec
+			cfg.StrictPassthrough = true
+
+			var opts testFlags
+			remaining, positional, err := Sift(&opts, tt.args, tt.passthroughWithArg, &cfg)
+
+			if tt.wantUnknown != "" {
+				var unknown *UnknownOptionError
+				require.ErrorAs(t, err, &unknown)
+				require.ErrorIs(t, err, ErrParse)
+				assert.Equal(t, tt.wantUnknown, unknown.Option)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemaining, remaining)
+			assert.Equal(t, tt.wantPositional, positional)
+		})
+	}
+}
+
+func TestParse_UnknownOptionError(t *testing.T) {
+	t.Parallel()
+
+	var opts testFlags
+	_, err := Parse(&opts, []string{"-v", "--nope=1"}, nil)
+
+	var unknown *UnknownOptionError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, "--nope", unknown.Option)
+	assert.EqualError(t, err, "argument parsing error: unknown option --nope")
+}
+
+func TestSift_OptionalArgumentPassthrough(t *testing.T) {
+	t.Parallel()
+
+	var spec PassthroughSpec
+	require.NoError(t, spec.AddLongOptions(LongOption{Name: "color", HasArg: OptionalArgument}))
+	require.NoError(t, spec.AddFlags(OptionalArgument, "-O"))
+
+	tests := map[string]struct {
+		args           []string
+		cfg            Config
+		wantRemaining  []string
+		wantPositional []string
+		wantArgs       []Arg
+	}{
+		"long alone": {
+			args:           []string{"--color", "src"},
+			wantRemaining:  []string{"--color"},
+			wantPositional: []string{"src"},
+		},
+		"long with value": {
+			args:           []string{"--color=auto", "src"},
+			wantRemaining:  []string{"--color=auto"},
+			wantPositional: []string{"src"},
+		},
+		"long with empty value": {
+			args:          []string{"--color="},
+			wantRemaining: []string{"--color="},
+		},
+		"next element looks like a flag": {
+			args:          []string{"--color", "-v"},
+			wantRemaining: []string{"--color"},
+		},
+		"short alone": {
+			args:           []string{"-O", "2"},
+			wantRemaining:  []string{"-O"},
+			wantPositional: []string{"2"},
+		},
+		"short with value": {
+			args:           []string{"-O2", "main.c"},
+			wantRemaining:  []string{"-O2"},
+			wantPositional: []string{"main.c"},
+		},
+		"short after known flag in chain": {
+			args:          []string{"-vOs"},
+			wantRemaining: []string{"-Os"},
+		},
+		"single dash long": {
+			args:           []string{"-color", "auto"},
+			cfg:            Config{SingleDashLong: true},
+			wantRemaining:  []string{"-color"},
+			wantPositional: []string{"auto"},
+		},
+		"ordered": {
+			args:           []string{"--color", "a", "--color=b"},
+			wantRemaining:  []string{"--color", "--color=b"},
+			wantPositional: []string{"a"},
+			wantArgs: []Arg{
+				{Value: "--color", Kind: ArgPassthrough, Index: 0},
+				{Value: "a", Kind: ArgPositional, Index: 1},
+				{Value: "--color=b", Kind: ArgPassthrough, Index: 2},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Passthrough = &spec
+
+			var opts testFlags
+			res, err := SiftDetailed(&opts, tt.args, nil, &cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemaining, res.Remaining)
+			assert.Equal(t, tt.wantPositional, res.Positional)
+
+			if tt.wantArgs != nil {
+				assert.Equal(t, tt.wantArgs, res.Args)
+			}
+		})
+	}
+}
+
+func TestPassthroughSpec_AddFlagsArity(t *testing.T) {
+	t.Parallel()
+
+	var spec PassthroughSpec
+	require.NoError(t, spec.AddFlagsArity(2, "--pos", "-P"))
+	require.NoError(t, spec.AddFlagsArity(0, "--off"))
+
+	assert.Equal(t, map[string]passthroughFlag{
+		"--pos": {hasArg: RequiredArgument, arity: 2},
+		"-P":    {hasArg: RequiredArgument, arity: 2},
+		"--off": {hasArg: NoArgument},
+	}, spec.flags)
+
+	require.ErrorContains(t, spec.AddFlagsArity(-1, "--neg"), "invalid arity -1")
+	require.ErrorContains(t, spec.AddFlagsArity(3, "--ok", "bad"), `invalid flag name "bad"`)
+	assert.Len(t, spec.flags, 3)
+}
+
+func TestSift_PassthroughArity(t *testing.T) {
+	t.Parallel()
+
+	var spec PassthroughSpec
+	require.NoError(t, spec.AddFlagsArity(2, "--pos", "-P"))
+	require.NoError(t, spec.AddFlagsArity(3, "--rgb"))
+
+	tests := map[string]struct {
+		args           []string
+		cfg            Config
+		wantRemaining  []string
+		wantPositional []string
+		wantErr        string
+	}{
+		"long": {
+			args:           []string{"--pos", "0", "1", "out"},
+			wantRemaining:  []string{"--pos", "0", "1"},
+			wantPositional: []string{"out"},
+		},
+		"values look like flags": {
+			args:          []string{"--rgb", "-1", "--", "-v"},
+			wantRemaining: []string{"--rgb", "-1", "--", "-v"},
+		},
+		"long with attached first value": {
+			args:           []string{"--pos=0", "1", "out"},
+			wantRemaining:  []string{"--pos=0", "1"},
+			wantPositional: []string{"out"},
+		},
+		"short": {
+			args:           []string{"-P", "0", "1", "out"},
+			wantRemaining:  []string{"-P", "0", "1"},
+			wantPositional: []string{"out"},
+		},
+		"short with attached first value": {
+			args:           []string{"-vP0", "1", "out"},
+			wantRemaining:  []string{"-P0", "1"},
+			wantPositional: []string{"out"},
+		},
+		"fewer values forwarded": {
+			args:          []string{"--rgb", "1", "2"},
+			wantRemaining: []string{"--rgb", "1", "2"},
+		},
+		"fewer values rejected": {
+			args:    []string{"--rgb", "1", "2"},
+			cfg:     Config{RequirePassthroughValues: true},
+			wantErr: "missing value for --rgb",
+		},
+		"fewer short values rejected": {
+			args:    []string{"-P0"},
+			cfg:     Config{RequirePassthroughValues: true},
+			wantErr: "missing value for -P",
+		},
+		"passthroughWithArg value rejected": {
+			args:    []string{"-x"},
+			cfg:     Config{RequirePassthroughValues: true},
+			wantErr: "missing value for -x",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Passthrough = &spec
+
+			var opts testFlags
+			remaining, positional, err := Sift(&opts, tt.args, []string{"-x"}, &cfg)
+
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, ErrParse)
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemaining, remaining)
+			assert.Equal(t, tt.wantPositional, positional)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package argsieve
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// maxResponseFileDepth limits how deeply response files may include each other.
+const maxResponseFileDepth = 32
+
+// osFS is an [fs.FS] over the operating system's file system. Unlike
+// [os.DirFS] it accepts absolute and relative paths as given, matching how
+// compilers resolve @file arguments.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) { return os.Open(name) }
+
+// expandResponseFiles replaces every @path argument with the arguments
+// read from path. Response files may reference other response files.
+// An argument naming a file that does not exist is kept literally.
+func (s *sieve) expandResponseFiles(args []string, stack []string) ([]string, error) {
+	var expanded []string
+
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '@' {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		name := path.Clean(arg[1:])
+
+		if slices.Contains(stack, name) {
+			return nil, fmt.Errorf("%w: response file %s includes itself", ErrParse, arg[1:])
+		}
+
+		if len(stack) >= maxResponseFileDepth {
+			return nil, fmt.Errorf("%w: response file %s nested too deeply", ErrParse, arg[1:])
+		}
+
+		data, err := fs.ReadFile(s.cfg.FS, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading response file: %v", ErrParse, err)
+		}
+
+		words, err := splitResponseFile(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: response file %s: %v", ErrParse, arg[1:], err)
+		}
+
+		words, err = s.expandResponseFiles(words, append(stack, name))
+		if err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, words...)
+	}
+
+	return expanded, nil
+}
+
+// splitResponseFile splits response file contents into arguments using
+// GCC rules: arguments are separated by whitespace, single and double
+// quotes group text (including whitespace), and a backslash escapes the
+// next character anywhere.
+func splitResponseFile(data string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, c := range data {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
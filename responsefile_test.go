@@ -0,0 +1,203 @@
+package argsieve
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitResponseFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		"whitespace separated": {
+			data: "-v  --region\tus-west-2\n\nhost\r\n",
+			want: []string{"-v", "--region", "us-west-2", "host"},
+		},
+		"double quotes": {
+			data: `--name "hello world" "a'b"`,
+			want: []string{"--name", "hello world", "a'b"},
+		},
+		"single quotes": {
+			data: `'hello world' 'a"b'`,
+			want: []string{"hello world", `a"b`},
+		},
+		"quotes join adjacent text": {
+			data: `--name="hello world"x`,
+			want: []string{"--name=hello worldx"},
+		},
+		"empty quotes yield empty arg": {
+			data: `a "" b`,
+			want: []string{"a", "", "b"},
+		},
+		"backslash escapes": {
+			data: `hello\ world \"q\" "a\"b" \\`,
+			want: []string{"hello world", `"q"`, `a"b`, `\`},
+		},
+		"empty file": {
+			data: " \n ",
+		},
+		"unterminated quote": {
+			data:    `"abc`,
+			wantErr: true,
+		},
+		"trailing backslash": {
+			data:    `abc\`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := splitResponseFile(tc.data)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSift_ResponseFiles(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"flags.rsp":   {Data: []byte("-v --region us-west-2 -x")},
+		"nested.rsp":  {Data: []byte("-d @flags.rsp")},
+		"quoted.rsp":  {Data: []byte(`--profile "my profile" 'host name'`)},
+		"self.rsp":    {Data: []byte("@self.rsp")},
+		"a.rsp":       {Data: []byte("@b.rsp")},
+		"b.rsp":       {Data: []byte("@a.rsp")},
+		"bad.rsp":     {Data: []byte(`"unterminated`)},
+		"sub/dir.rsp": {Data: []byte("-v")},
+	}
+
+	tests := map[string]struct {
+		args           []string
+		wantRemaining  []string
+		wantPositional []string
+		wantRegion     string
+		wantProfile    string
+		wantVerbose    bool
+		wantDebug      bool
+		wantErr        bool
+	}{
+		"expands file": {
+			args:           []string{"@flags.rsp", "host"},
+			wantVerbose:    true,
+			wantRegion:     "us-west-2",
+			wantRemaining:  []string{"-x"},
+			wantPositional: []string{"host"},
+		},
+		"nested file": {
+			args:          []string{"@nested.rsp"},
+			wantDebug:     true,
+			wantVerbose:   true,
+			wantRegion:    "us-west-2",
+			wantRemaining: []string{"-x"},
+		},
+		"quoted arguments": {
+			args:           []string{"@quoted.rsp"},
+			wantProfile:    "my profile",
+			wantPositional: []string{"host name"},
+		},
+		"subdirectory path": {
+			args:        []string{"@sub/dir.rsp"},
+			wantVerbose: true,
+		},
+		"missing file kept literally": {
+			args:           []string{"@missing.rsp", "user@host"},
+			wantPositional: []string{"@missing.rsp", "user@host"},
+		},
+		"lone at sign kept literally": {
+			args:           []string{"@"},
+			wantPositional: []string{"@"},
+		},
+		"flag value is expanded too": {
+			args:        []string{"--profile", "@quoted.rsp"},
+			wantProfile: "--profile",
+			wantPositional: []string{
+				"my profile",
+				"host name",
+			},
+		},
+		"self reference": {
+			args:    []string{"@self.rsp"},
+			wantErr: true,
+		},
+		"indirect cycle": {
+			args:    []string{"@a.rsp"},
+			wantErr: true,
+		},
+		"invalid quoting": {
+			args:    []string{"@bad.rsp"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags testFlags
+			cfg := &Config{ResponseFiles: true, FS: fsys}
+			remaining, positional, err := Sift(&flags, tc.args, nil, cfg)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrParse)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRemaining, remaining, "remaining")
+			assert.Equal(t, tc.wantPositional, positional, "positional")
+			assert.Equal(t, tc.wantRegion, flags.Region, "region")
+			assert.Equal(t, tc.wantProfile, flags.Profile, "profile")
+			assert.Equal(t, tc.wantVerbose, flags.Verbose, "verbose")
+			assert.Equal(t, tc.wantDebug, flags.Debug, "debug")
+		})
+	}
+}
+
+func TestParse_ResponseFilesDepthLimit(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{}
+	for i := range maxResponseFileDepth + 1 {
+		fsys[fmt.Sprintf("%d.rsp", i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("@%d.rsp", i+1))}
+	}
+
+	var flags testFlags
+	_, err := Parse(&flags, []string{"@0.rsp"}, &Config{ResponseFiles: true, FS: fsys})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrParse)
+	assert.Contains(t, err.Error(), "nested too deeply")
+}
+
+func TestParse_ResponseFilesDisabled(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{"flags.rsp": {Data: []byte("-v")}}
+
+	var flags testFlags
+	positional, err := Parse(&flags, []string{"@flags.rsp"}, &Config{FS: fsys})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@flags.rsp"}, positional)
+	assert.False(t, flags.Verbose)
+}
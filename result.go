@@ -0,0 +1,182 @@
+package argsieve
+
+// ArgKind classifies an argument in [Result.Args].
+type ArgKind int
+
+const (
+	// ArgKnown is an argument consumed by a known flag: the flag itself,
+	// a chain of short flags containing a known one, or a known flag's
+	// separate value.
+	ArgKnown ArgKind = iota
+
+	// ArgPassthrough is an unknown flag forwarded to remaining.
+	ArgPassthrough
+
+	// ArgPassthroughValue is the separate value of a forwarded flag.
+	ArgPassthroughValue
+
+	// ArgPositional is a positional argument.
+	ArgPositional
+
+	// ArgDelimiter is the "--" delimiter.
+	ArgDelimiter
+)
+
+// String returns the name of the kind.
+func (k ArgKind) String() string {
+	switch k {
+	case ArgKnown:
+		return "known"
+	case ArgPassthrough:
+		return "passthrough"
+	case ArgPassthroughValue:
+		return "passthrough-value"
+	case ArgPositional:
+		return "positional"
+	case ArgDelimiter:
+		return "delimiter"
+	default:
+		return "unknown"
+	}
+}
+
+// Arg is one entry of the ordered argument list in [Result.Args].
+type Arg struct {
+	// Value is the argument as it appears in remaining or positional.
+	// For ArgKnown and ArgDelimiter it is the original argument.
+	Value string
+
+	// Kind classifies the argument.
+	Kind ArgKind
+
+	// Index is the position of the originating argument in args (after
+	// response file expansion, see [Config.ResponseFiles]).
+	Index int
+}
+
+// Result is the detailed outcome of [SiftDetailed].
+type Result struct {
+	// Remaining holds unknown flags and their values, as returned by [Sift].
+	Remaining []string
+
+	// Positional holds positional arguments, as returned by [Sift].
+	Positional []string
+
+	// BeforeDelimiter holds the positional arguments before the first
+	// delimiter ("--" unless [Config.Delimiters] is set), and
+	// AfterDelimiter every argument after it. Together they make up
+	// Positional. Without a delimiter, BeforeDelimiter is Positional and
+	// AfterDelimiter is empty.
+	BeforeDelimiter []string
+	AfterDelimiter  []string
+
+	// DelimiterSeen reports whether args contained a delimiter,
+	// telling "tool a -- b" apart from "tool a b".
+	DelimiterSeen bool
+
+	// Sections splits the positional arguments at each delimiter (see
+	// [Config.Delimiters]). The first section holds the positional
+	// arguments before the first delimiter and has an empty Delimiter;
+	// each delimiter in the arguments after it starts a new section.
+	// "a -- b -- c" yields sections [a], -- [b] and -- [c].
+	Sections []Section
+
+	// Args lists every argument in its original order, tagged with how
+	// it was classified. Remaining and Positional entries appear in the
+	// same relative order as in those slices, so a wrapper can rebuild
+	// the original interleaving.
+	//
+	// An argument may produce several entries: a chain such as "-xv"
+	// with unknown -x yields a passthrough "-x" and a known "-xv", both
+	// with the chain's index. Flags translated by a forward tag appear as
+	// passthrough entries with the index of the argument they came from.
+	Args []Arg
+}
+
+// Section is a run of positional arguments in [Result.Sections].
+type Section struct {
+	// Delimiter is the delimiter that started the section, or empty for
+	// the first section.
+	Delimiter string
+
+	// Args holds the positional arguments of the section.
+	Args []string
+}
+
+// SiftDetailed is like [Sift] but also reports the original order of the
+// arguments, which Sift loses by splitting them into two slices, and which
+// positional arguments came after the "--" delimiter.
+//
+// Example:
+//
+//	res, err := argsieve.SiftDetailed(&opts, []string{"-v", "-x", "in", "-y"}, nil, nil)
+//	// res.Args: [{-v known 0} {-x passthrough 1} {in positional 2} {-y passthrough 3}]
+//
+// Panics if target is not a pointer to struct or if any tagged field
+// has an unsupported type.
+func SiftDetailed(target any, args []string, passthroughWithArg []string, cfg *Config) (*Result, error) {
+	return NewParser(target, cfg).SiftDetailed(target, args, passthroughWithArg)
+}
+
+// SiftDetailed is like the package-level [SiftDetailed] using the Parser's
+// configuration.
+//
+// Panics if target is not a non-nil pointer to the Parser's struct type.
+func (p *Parser) SiftDetailed(target any, args []string, passthroughWithArg []string) (*Result, error) {
+	s := p.newSieve(target, false)
+	s.passthrough = passthroughWithArg
+	s.recordOrder = true
+
+	remaining, positional, err := s.parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Remaining:       remaining,
+		Positional:      positional,
+		BeforeDelimiter: positional,
+		DelimiterSeen:   s.delimiterSeen,
+		Args:            s.ordered,
+		Sections:        s.sections(),
+	}
+
+	if s.delimiterSeen {
+		res.BeforeDelimiter = nil
+
+		if n := s.delimiterAt; n > 0 {
+			res.BeforeDelimiter = positional[:n:n]
+		}
+
+		if n := s.delimiterAt; n < len(positional) {
+			res.AfterDelimiter = positional[n:]
+		}
+	}
+
+	return res, nil
+}
+
+// sections splits the positional arguments recorded in ordered at each
+// delimiter.
+func (s *sieve) sections() []Section {
+	sections := []Section{{}}
+	delimited := false
+
+	for _, arg := range s.ordered {
+		last := &sections[len(sections)-1]
+
+		switch {
+		case arg.Kind == ArgDelimiter:
+			delimited = true
+			sections = append(sections, Section{Delimiter: arg.Value})
+		case arg.Kind != ArgPositional:
+			continue
+		case delimited && s.lexer.isDelimiter(arg.Value):
+			sections = append(sections, Section{Delimiter: arg.Value})
+		default:
+			last.Args = append(last.Args, arg.Value)
+		}
+	}
+
+	return sections
+}
@@ -0,0 +1,326 @@
+package argsieve
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiftDetailed(t *testing.T) {
+	t.Parallel()
+
+	type forwardFlags struct {
+		testFlags
+		Token string `long:"token" forward:"-t"`
+	}
+
+	tests := map[string]struct {
+		args               []string
+		passthroughWithArg []string
+		cfg                *Config
+		want               []Arg
+	}{
+		"interleaved": {
+			args: []string{"-v", "-x", "in", "-y", "--region", "eu", "out"},
+			want: []Arg{
+				{Value: "-v", Kind: ArgKnown, Index: 0},
+				{Value: "-x", Kind: ArgPassthrough, Index: 1},
+				{Value: "in", Kind: ArgPositional, Index: 2},
+				{Value: "-y", Kind: ArgPassthrough, Index: 3},
+				{Value: "--region", Kind: ArgKnown, Index: 4},
+				{Value: "eu", Kind: ArgKnown, Index: 5},
+				{Value: "out", Kind: ArgPositional, Index: 6},
+			},
+		},
+		"passthrough values": {
+			args:               []string{"-o", "opt", "file", "--opt", "v", "-oattached"},
+			passthroughWithArg: []string{"-o", "--opt"},
+			want: []Arg{
+				{Value: "-o", Kind: ArgPassthrough, Index: 0},
+				{Value: "opt", Kind: ArgPassthroughValue, Index: 1},
+				{Value: "file", Kind: ArgPositional, Index: 2},
+				{Value: "--opt", Kind: ArgPassthrough, Index: 3},
+				{Value: "v", Kind: ArgPassthroughValue, Index: 4},
+				{Value: "-oattached", Kind: ArgPassthrough, Index: 5},
+			},
+		},
+		"mixed chain": {
+			args: []string{"-vxdfrus", "-w"},
+			want: []Arg{
+				{Value: "-vxdfrus", Kind: ArgKnown, Index: 0},
+				{Value: "-x", Kind: ArgPassthrough, Index: 0},
+				{Value: "-f", Kind: ArgPassthrough, Index: 0},
+				{Value: "-w", Kind: ArgPassthrough, Index: 1},
+			},
+		},
+		"delimiter": {
+			args: []string{"a", "--", "-v", "b"},
+			want: []Arg{
+				{Value: "a", Kind: ArgPositional, Index: 0},
+				{Value: "--", Kind: ArgDelimiter, Index: 1},
+				{Value: "-v", Kind: ArgPositional, Index: 2},
+				{Value: "b", Kind: ArgPositional, Index: 3},
+			},
+		},
+		"forwarded flag": {
+			args: []string{"-x", "--token", "abc", "-y"},
+			want: []Arg{
+				{Value: "-x", Kind: ArgPassthrough, Index: 0},
+				{Value: "--token", Kind: ArgKnown, Index: 1},
+				{Value: "abc", Kind: ArgKnown, Index: 2},
+				{Value: "-t", Kind: ArgPassthrough, Index: 1},
+				{Value: "abc", Kind: ArgPassthroughValue, Index: 2},
+				{Value: "-y", Kind: ArgPassthrough, Index: 3},
+			},
+		},
+		"stop at first positional": {
+			args: []string{"-v", "cmd", "-x"},
+			cfg:  &Config{StopAtFirstPositional: true},
+			want: []Arg{
+				{Value: "-v", Kind: ArgKnown, Index: 0},
+				{Value: "cmd", Kind: ArgPositional, Index: 1},
+				{Value: "-x", Kind: ArgPositional, Index: 2},
+			},
+		},
+		"response file indexes are expanded": {
+			args: []string{"@a.rsp", "c"},
+			cfg:  &Config{ResponseFiles: true, FS: fstest.MapFS{"a.rsp": {Data: []byte("-x b")}}},
+			want: []Arg{
+				{Value: "-x", Kind: ArgPassthrough, Index: 0},
+				{Value: "b", Kind: ArgPositional, Index: 1},
+				{Value: "c", Kind: ArgPositional, Index: 2},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags forwardFlags
+			res, err := SiftDetailed(&flags, tc.args, tc.passthroughWithArg, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, res.Args)
+
+			// Remaining and Positional match Sift and the ordered list
+			var sifted forwardFlags
+			remaining, positional, err := Sift(&sifted, tc.args, tc.passthroughWithArg, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, remaining, res.Remaining)
+			assert.Equal(t, positional, res.Positional)
+			assert.Equal(t, sifted, flags)
+
+			var fromArgs []string
+			for _, arg := range res.Args {
+				if arg.Kind == ArgPassthrough || arg.Kind == ArgPassthroughValue {
+					fromArgs = append(fromArgs, arg.Value)
+				}
+			}
+			assert.Equal(t, remaining, fromArgs)
+		})
+	}
+}
+
+func TestSiftDetailed_Delimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args               []string
+		passthroughWithArg []string
+		cfg                *Config
+		wantRemaining      []string
+		wantBefore         []string
+		wantAfter          []string
+		wantSeen           bool
+	}{
+		"no delimiter": {
+			args:          []string{"a", "-x", "b"},
+			wantBefore:    []string{"a", "b"},
+			wantRemaining: []string{"-x"},
+		},
+		"split": {
+			args:          []string{"a", "-x", "--", "b", "--", "-v"},
+			wantRemaining: []string{"-x"},
+			wantBefore:    []string{"a"},
+			wantAfter:     []string{"b", "--", "-v"},
+			wantSeen:      true,
+		},
+		"nothing after": {
+			args:       []string{"a", "--"},
+			wantBefore: []string{"a"},
+			wantSeen:   true,
+		},
+		"nothing before": {
+			args:      []string{"--", "a"},
+			wantAfter: []string{"a"},
+			wantSeen:  true,
+		},
+		"delimiter as flag value": {
+			args:               []string{"-x", "--", "a"},
+			passthroughWithArg: []string{"-x"},
+			wantRemaining:      []string{"-x", "--"},
+			wantBefore:         []string{"a"},
+		},
+		"drained by stop at first positional": {
+			args:       []string{"cmd", "--", "a"},
+			cfg:        &Config{StopAtFirstPositional: true},
+			wantBefore: []string{"cmd", "--", "a"},
+		},
+		"keep delimiter": {
+			args:          []string{"-x", "a", "--", "b"},
+			cfg:           &Config{KeepDelimiter: true},
+			wantRemaining: []string{"-x", "--"},
+			wantBefore:    []string{"a"},
+			wantAfter:     []string{"b"},
+			wantSeen:      true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags testFlags
+			res, err := SiftDetailed(&flags, tc.args, tc.passthroughWithArg, tc.cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantRemaining, res.Remaining)
+			assert.Equal(t, tc.wantBefore, res.BeforeDelimiter)
+			assert.Equal(t, tc.wantAfter, res.AfterDelimiter)
+			assert.Equal(t, tc.wantSeen, res.DelimiterSeen)
+			assert.Equal(t, res.Positional, append(res.BeforeDelimiter, res.AfterDelimiter...))
+		})
+	}
+}
+
+func TestParse_KeepDelimiterIgnored(t *testing.T) {
+	t.Parallel()
+
+	var flags testFlags
+	positional, err := Parse(&flags, []string{"-v", "--", "a"}, &Config{KeepDelimiter: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, positional)
+}
+
+func TestSiftDetailed_Sections(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args          []string
+		cfg           *Config
+		wantSections  []Section
+		wantRemaining []string
+		wantAfter     []string
+	}{
+		"no delimiter": {
+			args:         []string{"a", "b"},
+			wantSections: []Section{{Args: []string{"a", "b"}}},
+		},
+		"empty": {
+			args:         nil,
+			wantSections: []Section{{}},
+		},
+		"several sections": {
+			args: []string{"a", "--", "b", "--", "--", "c"},
+			wantSections: []Section{
+				{Args: []string{"a"}},
+				{Delimiter: "--", Args: []string{"b"}},
+				{Delimiter: "--"},
+				{Delimiter: "--", Args: []string{"c"}},
+			},
+			wantAfter: []string{"b", "--", "--", "c"},
+		},
+		"custom delimiter": {
+			args: []string{"-v", "./pkg", "-args", "-test.v", "--", "x"},
+			cfg:  &Config{Delimiters: []string{"-args"}},
+			wantSections: []Section{
+				{Args: []string{"./pkg"}},
+				{Delimiter: "-args", Args: []string{"-test.v", "--", "x"}},
+			},
+			wantAfter: []string{"-test.v", "--", "x"},
+		},
+		"several custom delimiters": {
+			args: []string{"run", "img", "--", "sh", "::", "-c", "--", "ls"},
+			cfg:  &Config{Delimiters: []string{"--", "::"}},
+			wantSections: []Section{
+				{Args: []string{"run", "img"}},
+				{Delimiter: "--", Args: []string{"sh"}},
+				{Delimiter: "::", Args: []string{"-c"}},
+				{Delimiter: "--", Args: []string{"ls"}},
+			},
+			wantAfter: []string{"sh", "::", "-c", "--", "ls"},
+		},
+		"double dash positional when not a delimiter": {
+			args:          []string{"a", "--", "-x"},
+			cfg:           &Config{Delimiters: []string{"-args"}},
+			wantSections:  []Section{{Args: []string{"a", "--"}}},
+			wantRemaining: []string{"-x"},
+		},
+		"delimiter drained by stop at first positional": {
+			args:         []string{"cmd", "--", "a"},
+			cfg:          &Config{StopAtFirstPositional: true},
+			wantSections: []Section{{Args: []string{"cmd", "--", "a"}}},
+		},
+		"keep custom delimiter": {
+			args:          []string{"-x", "-args", "b"},
+			cfg:           &Config{Delimiters: []string{"-args"}, KeepDelimiter: true},
+			wantSections:  []Section{{}, {Delimiter: "-args", Args: []string{"b"}}},
+			wantRemaining: []string{"-x", "-args"},
+			wantAfter:     []string{"b"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var flags testFlags
+			res, err := SiftDetailed(&flags, tc.args, nil, tc.cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantSections, res.Sections)
+			assert.Equal(t, tc.wantRemaining, res.Remaining)
+			assert.Equal(t, tc.wantAfter, res.AfterDelimiter)
+		})
+	}
+}
+
+func TestParse_CustomDelimiterRequired(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Delimiters: []string{"-args"}, RequirePositionalDelimiter: true}
+
+	var flags testFlags
+	_, err := Parse(&flags, []string{"-v", "pkg"}, cfg)
+	require.ErrorIs(t, err, ErrParse)
+	assert.ErrorContains(t, err, `positional argument "pkg" not allowed before "-args" delimiter`)
+
+	positional, err := Parse(&flags, []string{"-v", "-args", "pkg"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pkg"}, positional)
+}
+
+func TestSiftDetailed_Error(t *testing.T) {
+	t.Parallel()
+
+	var flags testFlags
+	res, err := SiftDetailed(&flags, []string{"--region"}, nil, nil)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrParse)
+	assert.Nil(t, res)
+}
+
+func TestArgKind_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "known", ArgKnown.String())
+	assert.Equal(t, "passthrough", ArgPassthrough.String())
+	assert.Equal(t, "passthrough-value", ArgPassthroughValue.String())
+	assert.Equal(t, "positional", ArgPositional.String())
+	assert.Equal(t, "delimiter", ArgDelimiter.String())
+	assert.Equal(t, "unknown", ArgKind(99).String())
+}
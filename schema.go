@@ -0,0 +1,536 @@
+package argsieve
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// fieldSpec describes a tagged struct field independently of any value.
+type fieldSpec struct {
+	index    []int  // index sequence for reflect.Value.FieldByIndex
+	name     string // Go field path, e.g. "Base.Region"
+	short    string
+	long     string
+	needsArg bool
+	typ      reflect.Type
+	tag      reflect.StructTag
+	isPtr    bool   // true if field is a pointer to TextUnmarshaler
+	fromFile bool   // true if @path and file:path values are read from files
+	numeric  bool   // true if field receives -<digits> shorthand
+	forward  string // flag name Sift appends to remaining, if any
+	value    bool   // true if field is set through flag.Value
+
+	// unsupported explains why values of the field's type cannot be
+	// parsed without a [ValueParser], or is empty if they can.
+	unsupported string
+}
+
+// schema is the flag layout of an options struct type, extracted once
+// and shared by every parse of that type.
+type schema struct {
+	typ         reflect.Type
+	fields      []*fieldSpec
+	flags       map[string]*fieldSpec // prefixed flag name (-x, --name) → field
+	numeric     *fieldSpec            // field receiving -<digits> shorthand
+	maxShortLen int                   // longest short name, for longest-match lookup
+	errs        []error               // problems found while compiling
+	dups        []error               // flag names declared twice, reported by Validate only
+}
+
+// schemaEntry is a cached compileSchema result.
+type schemaEntry struct {
+	schema *schema
+	err    error
+}
+
+// schemaCache maps reflect.Type to *schemaEntry.
+var schemaCache sync.Map
+
+// loadSchema returns the schema for struct type t, compiling it on first use.
+// Safe for concurrent use.
+func loadSchema(t reflect.Type) (*schema, error) {
+	if e, ok := schemaCache.Load(t); ok {
+		entry := e.(*schemaEntry)
+		return entry.schema, entry.err
+	}
+
+	sc, err := compileSchema(t)
+	e, _ := schemaCache.LoadOrStore(t, &schemaEntry{schema: sc, err: err})
+	entry := e.(*schemaEntry)
+
+	return entry.schema, entry.err
+}
+
+// tagKeys lists the struct tag keys argsieve reads.
+var tagKeys = []string{"short", "long", "numeric", "fromfile", "forward"}
+
+// compileSchema reads struct tags of t, including fields of embedded
+// structs. The returned schema is never nil; the error joins every
+// problem found, each wrapping [ErrSchema].
+func compileSchema(t reflect.Type) (*schema, error) {
+	sc := &schema{
+		typ:   t,
+		flags: make(map[string]*fieldSpec),
+	}
+
+	sc.extractFields(t, nil, "")
+	sc.checkDigitShorts()
+
+	return sc, errors.Join(sc.errs...)
+}
+
+// checkDigitShorts reports short names made of digits when a field is
+// tagged numeric: -<digits> always goes to the numeric field, so such a
+// flag could never be set.
+func (sc *schema) checkDigitShorts() {
+	if sc.numeric == nil {
+		return
+	}
+
+	for _, spec := range sc.fields {
+		if spec.short != "" && isNumericShorthand("-"+spec.short) {
+			sc.errorf("field %s has short tag %q, which is read as -<digits> shorthand for numeric field %s",
+				spec.name, spec.short, sc.numeric.name)
+		}
+	}
+}
+
+// extractFields recursively extracts fields from struct type t,
+// including fields from embedded structs. index and path locate t
+// within the schema's root type.
+func (sc *schema) extractFields(t reflect.Type, index []int, path string) {
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		fieldIndex := append(slices.Clip(index), i)
+		fieldPath := path + fieldType.Name
+
+		// Recursively process embedded structs
+		if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
+			sc.extractFields(fieldType.Type, fieldIndex, fieldPath+".")
+			continue
+		}
+
+		if err := checkTagSyntax(fieldType.Tag); err != nil {
+			sc.errorf("field %s has invalid struct tag: %v", fieldPath, err)
+			continue
+		}
+
+		short := fieldType.Tag.Get("short")
+		long := fieldType.Tag.Get("long")
+		numeric := sc.boolTag(fieldType.Tag, "numeric", fieldPath)
+
+		// Skip fields without tags
+		if short == "" && long == "" && !numeric {
+			continue
+		}
+
+		if !fieldType.IsExported() {
+			sc.errorf("field %s is unexported", fieldPath)
+			continue
+		}
+
+		// Determine field type and whether it needs an argument
+		kind := fieldType.Type.Kind()
+		spec := &fieldSpec{
+			index:    fieldIndex,
+			name:     fieldPath,
+			typ:      fieldType.Type,
+			tag:      fieldType.Tag,
+			short:    short,
+			long:     long,
+			fromFile: sc.boolTag(fieldType.Tag, "fromfile", fieldPath),
+			forward:  fieldType.Tag.Get("forward"),
+		}
+
+		if spec.forward != "" && (!strings.HasPrefix(spec.forward, "-") || spec.forward == "-" || spec.forward == "--") {
+			sc.errorf("field %s has invalid forward tag %q (must be a flag such as -p or --profile)", fieldPath, spec.forward)
+		}
+
+		switch {
+		case numeric:
+			// Integer field receiving -<digits> shorthand
+			if !isIntKind(kind) {
+				sc.errorf("numeric field %s must have an integer type, got %s", fieldPath, fieldType.Type)
+				continue
+			}
+			if sc.numeric != nil {
+				sc.errorf("field %s: only one field may be tagged numeric (already %s)", fieldPath, sc.numeric.name)
+				continue
+			}
+			spec.needsArg = true
+			spec.numeric = true
+			sc.numeric = spec
+		case isFlagValue(fieldType.Type):
+			// flag.Value - boolean if it reports IsBoolFlag
+			spec.value = true
+			spec.needsArg = !isBoolFlag(fieldType.Type)
+		case kind == reflect.Bool:
+			spec.needsArg = false
+		case kind == reflect.String:
+			spec.needsArg = true
+		case kind == reflect.Ptr:
+			// Pointer to TextUnmarshaler - nil when flag absent, allocated when present
+			spec.needsArg = true
+			if !reflect.PointerTo(fieldType.Type.Elem()).Implements(textUnmarshalerType) {
+				spec.unsupported = fmt.Sprintf(
+					"pointer field %s must point to type implementing encoding.TextUnmarshaler or flag.Value", fieldPath)
+				break
+			}
+			spec.isPtr = true
+		case reflect.PointerTo(fieldType.Type).Implements(textUnmarshalerType):
+			// Field's pointer type implements encoding.TextUnmarshaler
+			spec.needsArg = true
+		default:
+			// Only usable with a ValueParser, checked per configuration
+			spec.needsArg = true
+			spec.unsupported = fmt.Sprintf(
+				"field %s has unsupported type %s (must be string, bool, or implement encoding.TextUnmarshaler or flag.Value)",
+				fieldPath, fieldType.Type)
+		}
+
+		sc.fields = append(sc.fields, spec)
+
+		if short != "" && sc.checkName(spec, "short", short) {
+			if !utf8.ValidString(short) {
+				sc.errorf("field %s has invalid short tag %q (must be a single character)", fieldPath, short)
+			}
+			sc.addFlag(spec, "-"+short)
+			sc.maxShortLen = max(sc.maxShortLen, len(short))
+		}
+
+		if long != "" && sc.checkName(spec, "long", long) {
+			sc.addFlag(spec, "--"+long)
+		}
+	}
+}
+
+// addFlag registers spec under a prefixed flag name. A name declared
+// twice is recorded for Validate and goes to the later field, so that a
+// struct can override a flag of a struct it embeds.
+func (sc *schema) addFlag(spec *fieldSpec, flag string) {
+	if other, dup := sc.flags[flag]; dup {
+		sc.dups = append(sc.dups, fmt.Errorf("%w: flag %s is declared by both %s and %s",
+			ErrSchema, flag, other.name, spec.name))
+	}
+
+	sc.flags[flag] = spec
+}
+
+// checkName reports whether a short or long tag value is a usable flag name.
+func (sc *schema) checkName(spec *fieldSpec, key, name string) bool {
+	switch {
+	case strings.HasPrefix(name, "-"):
+		sc.errorf("field %s has invalid %s tag %q (must not start with \"-\")", spec.name, key, name)
+	case strings.Contains(name, "="):
+		sc.errorf("field %s has invalid %s tag %q (must not contain \"=\")", spec.name, key, name)
+	default:
+		return true
+	}
+
+	return false
+}
+
+// boolTag returns the boolean value of tag key, reporting unparsable values.
+func (sc *schema) boolTag(tag reflect.StructTag, key, path string) bool {
+	value, ok := tag.Lookup(key)
+	if !ok {
+		return false
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		sc.errorf("field %s has invalid %s tag %q (must be true or false)", path, key, value)
+	}
+
+	return b
+}
+
+// errorf records a schema error.
+func (sc *schema) errorf(format string, args ...any) {
+	sc.errs = append(sc.errs, fmt.Errorf("%w: "+format, append([]any{ErrSchema}, args...)...))
+}
+
+// checkTagSyntax reports malformed struct tags that mention argsieve keys,
+// using the conventional key:"value" syntax understood by
+// [reflect.StructTag.Get]. Tags without argsieve keys are not checked.
+func checkTagSyntax(tag reflect.StructTag) error {
+	if !slices.ContainsFunc(tagKeys, func(key string) bool { return strings.Contains(string(tag), key) }) {
+		return nil
+	}
+
+	for tag != "" {
+		// Skip leading space
+		tag = reflect.StructTag(strings.TrimLeft(string(tag), " "))
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon; a space, quote or control character is a syntax error
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return fmt.Errorf("bad syntax for struct tag pair in %q", string(tag))
+		}
+
+		key := string(tag[:i])
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+
+		if i >= len(tag) {
+			return fmt.Errorf("bad syntax for struct tag value of %s", key)
+		}
+
+		if _, err := strconv.Unquote(string(tag[:i+1])); err != nil {
+			return fmt.Errorf("bad syntax for struct tag value of %s", key)
+		}
+
+		tag = tag[i+1:]
+	}
+
+	return nil
+}
+
+// check validates the schema against settings that affect which fields
+// are allowed: short tags must be a single rune unless
+// [Config.MultiCharShort] is set, fromfile fields cannot be combined with
+// [Config.ResponseFiles], and fields of unsupported types need a
+// [ValueParser] (see checkTypes). It also rejects
+// [Config.RequirePositionalDelimiter] with no delimiters configured.
+func (sc *schema) check(cfg *Config) error {
+	var errs []error
+
+	if cfg.RequirePositionalDelimiter && cfg.Delimiters != nil && len(cfg.Delimiters) == 0 {
+		errs = append(errs, fmt.Errorf("%w: RequirePositionalDelimiter needs a delimiter, but Delimiters is empty",
+			ErrSchema))
+	}
+
+	for _, spec := range sc.fields {
+		if !cfg.MultiCharShort && spec.short != "" && utf8.ValidString(spec.short) &&
+			utf8.RuneCountInString(spec.short) != 1 {
+			errs = append(errs, fmt.Errorf("%w: field %s has invalid short tag %q (must be a single character)",
+				ErrSchema, spec.name, spec.short))
+		}
+
+		// A secret given as @path would be split into argv by response
+		// file expansion before the field could read it
+		if cfg.ResponseFiles && spec.fromFile {
+			errs = append(errs, fmt.Errorf("%w: field %s is tagged fromfile, which cannot be used with ResponseFiles",
+				ErrSchema, spec.name))
+		}
+	}
+
+	return errors.Join(append(errs, sc.checkTypes(cfg))...)
+}
+
+// checkTypes reports fields of unsupported types that have no
+// [ValueParser] in cfg, and FieldParsers keys that name no field.
+// cfg may be nil.
+func (sc *schema) checkTypes(cfg *Config) error {
+	var errs []error
+
+	for _, spec := range sc.fields {
+		if _, ok := cfg.valueParser(spec); !ok && spec.unsupported != "" {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrSchema, spec.unsupported))
+		}
+	}
+
+	if cfg != nil {
+		for _, name := range slices.Sorted(maps.Keys(cfg.FieldParsers)) {
+			if !slices.ContainsFunc(sc.fields, func(spec *fieldSpec) bool { return spec.name == name }) {
+				errs = append(errs, fmt.Errorf("%w: FieldParsers names unknown field %s", ErrSchema, name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every problem with the options struct target points to,
+// without parsing any arguments: tagged fields with unsupported types and
+// no [ValueParser] in cfg, flag names declared more than once (including
+// across embedded structs), malformed struct tags, flag names that start
+// with "-" or contain "=", and digit short names alongside a numeric
+// field. Use it in unit tests to check option structs before they are
+// used.
+//
+// Duplicate flag names are reported only by Validate. [Sift] and [Parse]
+// accept them and bind the flag to the field declared last, which lets a
+// struct override a flag of a struct it embeds.
+//
+// Only the type of target is used, so a nil pointer such as (*Options)(nil)
+// is accepted. The cfg parameter is the configuration the struct will be
+// parsed with; pass nil for defaults. It matters for settings such as
+// [Config.MultiCharShort] that change which tags are valid.
+//
+// The returned error joins one error per problem, each wrapping [ErrSchema].
+// Returns nil if [Sift] and [Parse] would accept target and it declares no
+// flag name twice.
+//
+// Example:
+//
+//	func TestOptions(t *testing.T) {
+//	    if err := argsieve.Validate((*Options)(nil), nil); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func Validate(target any, cfg *Config) error {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: target must be a pointer to struct, got %T", ErrSchema, target)
+	}
+
+	sc, err := loadSchema(t.Elem())
+
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	return errors.Join(err, errors.Join(sc.dups...), sc.check(cfg))
+}
+
+// Flag describes a flag declared by a tagged options struct field.
+type Flag struct {
+	// Short is the short name without the leading dash, or "" if none.
+	Short string
+
+	// Long is the long name without the leading dashes, or "" if none.
+	Long string
+
+	// Field is the Go field path, with embedded struct names separated
+	// by dots (e.g. "CommonFlags.Verbose").
+	Field string
+
+	// Index is the field's index sequence for [reflect.Value.FieldByIndex].
+	Index []int
+
+	// Type is the field's Go type.
+	Type reflect.Type
+
+	// Arity is the number of values the flag takes: 0 for boolean flags,
+	// 1 for flags that require a value.
+	Arity int
+
+	// Numeric reports whether the field receives -<digits> shorthand.
+	Numeric bool
+
+	// FromFile reports whether @path and file:path values are read from files.
+	FromFile bool
+
+	// Forward is the flag name [Sift] forwards the flag under, or "" if
+	// the flag is consumed.
+	Forward string
+
+	// Tag is the field's complete struct tag.
+	Tag reflect.StructTag
+}
+
+// Schema describes the flags declared by an options struct type.
+// It is read-only and safe for concurrent use.
+type Schema struct {
+	sc *schema
+}
+
+// SchemaOf returns the schema of the options struct target points to.
+// Only the type of target is used, so a nil pointer such as
+// (*Options)(nil) is accepted.
+//
+// Returns an error wrapping [ErrSchema] if target is not a pointer to
+// struct or the struct is invalid (see [Validate]). A struct with fields
+// that need a [ValueParser] is invalid here; use [Parser.Schema] instead.
+//
+// Example:
+//
+//	schema, err := argsieve.SchemaOf((*Options)(nil))
+//	for flag := range schema.Flags() {
+//	    fmt.Println(flag.Long, flag.Type)
+//	}
+func SchemaOf(target any) (*Schema, error) {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: target must be a pointer to struct, got %T", ErrSchema, target)
+	}
+
+	sc, err := loadSchema(t.Elem())
+	if err == nil {
+		err = sc.checkTypes(nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{sc: sc}, nil
+}
+
+// Schema returns the schema of the Parser's options struct type.
+func (p *Parser) Schema() *Schema {
+	return &Schema{sc: p.schema}
+}
+
+// Type returns the options struct type.
+func (s *Schema) Type() reflect.Type {
+	return s.sc.typ
+}
+
+// Flags returns the declared flags in struct field order, with fields of
+// embedded structs in place of the embedded field.
+func (s *Schema) Flags() iter.Seq[Flag] {
+	return func(yield func(Flag) bool) {
+		for _, spec := range s.sc.fields {
+			if !yield(spec.flag()) {
+				return
+			}
+		}
+	}
+}
+
+// Lookup returns the flag declared under name, given with its dashes
+// ("-v", "--verbose").
+func (s *Schema) Lookup(name string) (Flag, bool) {
+	spec, ok := s.sc.flags[name]
+	if !ok {
+		return Flag{}, false
+	}
+
+	return spec.flag(), true
+}
+
+// flag returns the exported description of spec.
+func (spec *fieldSpec) flag() Flag {
+	f := Flag{
+		Short:    spec.short,
+		Long:     spec.long,
+		Field:    spec.name,
+		Index:    slices.Clone(spec.index),
+		Type:     spec.typ,
+		Numeric:  spec.numeric,
+		FromFile: spec.fromFile,
+		Forward:  spec.forward,
+		Tag:      spec.tag,
+	}
+
+	if spec.needsArg {
+		f.Arity = 1
+	}
+
+	return f
+}
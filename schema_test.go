@@ -0,0 +1,293 @@
+package argsieve
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	type base struct {
+		Region string `short:"r" long:"region"`
+	}
+
+	type duplicates struct {
+		base
+		Remote string `short:"r"`
+		Other  string `long:"region"`
+	}
+
+	type unsupported struct {
+		Count float64   `short:"c"`
+		Items []string  `long:"items"`
+		Ptr   *struct{} `long:"ptr"`
+	}
+
+	type badNames struct {
+		Dash    bool `short:"-"`
+		Equals  bool `long:"a=b"`
+		Prefix  bool `long:"--name"`
+		Forward bool `long:"fwd" forward:"p"`
+	}
+
+	// Built at run time: go vet rejects malformed tags in source.
+	badTags := reflect.StructOf([]reflect.StructField{
+		{Name: "Unquoted", Type: reflect.TypeFor[bool](), Tag: `short:v`},
+		{Name: "Spaced", Type: reflect.TypeFor[bool](), Tag: `long: "verbose"`},
+		{Name: "Numeric", Type: reflect.TypeFor[int](), Tag: `numeric:"yes"`},
+	})
+
+	type unexported struct {
+		hidden string `long:"hidden"`
+	}
+
+	type multiChar struct {
+		NoCheck bool `short:"nc"`
+	}
+
+	type digitShort struct {
+		Lines int  `short:"n" numeric:"true"`
+		Four  bool `short:"4"`
+	}
+
+	type secret struct {
+		Token string `long:"token" fromfile:"true"`
+	}
+
+	type untaggedIgnored struct {
+		Count   float64
+		Verbose bool `short:"v" json:"verbose"`
+	}
+
+	tests := map[string]struct {
+		target   any
+		cfg      *Config
+		wantErrs []string
+	}{
+		"valid": {
+			target: (*testFlags)(nil),
+		},
+		"valid embedded": {
+			target: &testEmbedded{},
+		},
+		"untagged fields ignored": {
+			target: (*untaggedIgnored)(nil),
+		},
+		"duplicates across embedded structs": {
+			target: (*duplicates)(nil),
+			wantErrs: []string{
+				"flag -r is declared by both base.Region and Remote",
+				"flag --region is declared by both base.Region and Other",
+			},
+		},
+		"unsupported types": {
+			target: (*unsupported)(nil),
+			wantErrs: []string{
+				"field Count has unsupported type float64",
+				"field Items has unsupported type []string",
+				"pointer field Ptr must point to type implementing encoding.TextUnmarshaler",
+			},
+		},
+		"invalid names": {
+			target: (*badNames)(nil),
+			wantErrs: []string{
+				`field Dash has invalid short tag "-"`,
+				`field Equals has invalid long tag "a=b"`,
+				`field Prefix has invalid long tag "--name"`,
+				`field Forward has invalid forward tag "p"`,
+			},
+		},
+		"invalid tag syntax": {
+			target: reflect.New(badTags).Interface(),
+			wantErrs: []string{
+				"field Unquoted has invalid struct tag",
+				"field Spaced has invalid struct tag",
+				`field Numeric has invalid numeric tag "yes"`,
+			},
+		},
+		"digit short with numeric field": {
+			target:   (*digitShort)(nil),
+			wantErrs: []string{`field Four has short tag "4", which is read as -<digits> shorthand for numeric field Lines`},
+		},
+		"fromfile without response files": {
+			target: (*secret)(nil),
+		},
+		"fromfile with response files": {
+			target:   (*secret)(nil),
+			cfg:      &Config{ResponseFiles: true},
+			wantErrs: []string{"field Token is tagged fromfile, which cannot be used with ResponseFiles"},
+		},
+		"required delimiter with none configured": {
+			target:   (*testFlags)(nil),
+			cfg:      &Config{RequirePositionalDelimiter: true, Delimiters: []string{}},
+			wantErrs: []string{"RequirePositionalDelimiter needs a delimiter, but Delimiters is empty"},
+		},
+		"no delimiters": {
+			target: (*testFlags)(nil),
+			cfg:    &Config{Delimiters: []string{}},
+		},
+		"unexported field": {
+			target:   (*unexported)(nil),
+			wantErrs: []string{"field hidden is unexported"},
+		},
+		"multi-char short without config": {
+			target:   (*multiChar)(nil),
+			wantErrs: []string{`field NoCheck has invalid short tag "nc"`},
+		},
+		"multi-char short with config": {
+			target: (*multiChar)(nil),
+			cfg:    &Config{MultiCharShort: true},
+		},
+		"non-pointer target": {
+			target:   testFlags{},
+			wantErrs: []string{"target must be a pointer to struct"},
+		},
+		"nil target": {
+			target:   nil,
+			wantErrs: []string{"target must be a pointer to struct"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(tc.target, tc.cfg)
+
+			if len(tc.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrSchema)
+			assert.Len(t, strings.Split(err.Error(), "\n"), len(tc.wantErrs))
+			for _, want := range tc.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestSift_DuplicateFlagOverridesEmbedded(t *testing.T) {
+	t.Parallel()
+
+	type duplicates struct {
+		testEmbeddedBase
+		Remote string `short:"r"`
+	}
+
+	var flags duplicates
+	_, _, err := Sift(&flags, []string{"-r", "x", "--region", "y"}, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "x", flags.Remote)
+	assert.Equal(t, "y", flags.Region)
+}
+
+func TestSchemaOf(t *testing.T) {
+	t.Parallel()
+
+	type options struct {
+		testEmbeddedBase
+		Lines   int       `short:"n" numeric:"true"`
+		Token   string    `long:"token" fromfile:"true" json:"-"`
+		Level   *logLevel `long:"level"`
+		Verbose bool      `short:"v"`
+		ignored string
+	}
+
+	schema, err := SchemaOf((*options)(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, reflect.TypeFor[options](), schema.Type())
+
+	var flags []Flag
+	for flag := range schema.Flags() {
+		flags = append(flags, flag)
+	}
+
+	want := []Flag{
+		{
+			Short: "r", Long: "region", Field: "testEmbeddedBase.Region", Index: []int{0, 0},
+			Type: reflect.TypeFor[string](), Arity: 1, Tag: `short:"r" long:"region"`,
+		},
+		{
+			Short: "n", Field: "Lines", Index: []int{1},
+			Type: reflect.TypeFor[int](), Arity: 1, Numeric: true, Tag: `short:"n" numeric:"true"`,
+		},
+		{
+			Long: "token", Field: "Token", Index: []int{2},
+			Type: reflect.TypeFor[string](), Arity: 1, FromFile: true, Tag: `long:"token" fromfile:"true" json:"-"`,
+		},
+		{
+			Long: "level", Field: "Level", Index: []int{3},
+			Type: reflect.TypeFor[*logLevel](), Arity: 1, Tag: `long:"level"`,
+		},
+		{
+			Short: "v", Field: "Verbose", Index: []int{4},
+			Type: reflect.TypeFor[bool](), Tag: `short:"v"`,
+		},
+	}
+	assert.Equal(t, want, flags)
+
+	flag, ok := schema.Lookup("--region")
+	require.True(t, ok)
+	assert.Equal(t, "testEmbeddedBase.Region", flag.Field)
+
+	flag, ok = schema.Lookup("-v")
+	require.True(t, ok)
+	assert.Equal(t, "Verbose", flag.Field)
+
+	_, ok = schema.Lookup("v")
+	assert.False(t, ok)
+}
+
+func TestSchemaOf_FlagsIsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaOf((*testEmbedded)(nil))
+	require.NoError(t, err)
+
+	for flag := range schema.Flags() {
+		flag.Index[0] = 99
+	}
+
+	var flags testEmbedded
+	_, err = Parse(&flags, []string{"-r", "us-west-2"}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", flags.Region)
+}
+
+func TestSchemaOf_Errors(t *testing.T) {
+	t.Parallel()
+
+	type badStruct struct {
+		Count float64 `short:"c"`
+	}
+
+	_, err := SchemaOf((*badStruct)(nil))
+	assert.ErrorIs(t, err, ErrSchema)
+
+	_, err = SchemaOf(testFlags{})
+	assert.ErrorIs(t, err, ErrSchema)
+}
+
+func TestParser_Schema(t *testing.T) {
+	t.Parallel()
+
+	p := NewParser((*testFlags)(nil), nil)
+
+	var longs []string
+	for flag := range p.Schema().Flags() {
+		longs = append(longs, flag.Long)
+	}
+
+	assert.Equal(t, []string{"region", "profile", "verbose", "debug"}, longs)
+}
@@ -0,0 +1,60 @@
+package argsieve
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ParseAs is like [Parse] but constructs and returns the options value.
+//
+// T must be a struct type. The schema of T is validated on first use and
+// cached; an invalid schema is reported as an error wrapping [ErrSchema]
+// instead of a panic.
+//
+// Example:
+//
+//	type Options struct {
+//	    Output  string `short:"o" long:"output"`
+//	    Verbose bool   `short:"v"`
+//	}
+//	opts, positional, err := argsieve.ParseAs[Options](os.Args[1:], nil)
+func ParseAs[T any](args []string, cfg *Config) (opts T, positional []string, err error) {
+	p, err := parserFor[T](cfg)
+	if err != nil {
+		return opts, nil, err
+	}
+
+	positional, err = p.Parse(&opts, args)
+
+	return opts, positional, err
+}
+
+// SiftAs is like [Sift] but constructs and returns the options value.
+//
+// T must be a struct type. The schema of T is validated on first use and
+// cached; an invalid schema is reported as an error wrapping [ErrSchema]
+// instead of a panic.
+//
+// Example:
+//
+//	opts, remaining, positional, err := argsieve.SiftAs[Options](os.Args[1:], []string{"-o"}, nil)
+func SiftAs[T any](args []string, passthroughWithArg []string, cfg *Config) (opts T, remaining, positional []string, err error) {
+	p, err := parserFor[T](cfg)
+	if err != nil {
+		return opts, nil, nil, err
+	}
+
+	remaining, positional, err = p.Sift(&opts, args, passthroughWithArg)
+
+	return opts, remaining, positional, err
+}
+
+// parserFor returns a Parser for struct type T.
+func parserFor[T any](cfg *Config) (*Parser, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct type", ErrSchema, t)
+	}
+
+	return newParser(t, cfg)
+}
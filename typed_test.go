@@ -0,0 +1,71 @@
+package argsieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAs(t *testing.T) {
+	t.Parallel()
+
+	opts, positional, err := ParseAs[testFlags]([]string{"-v", "--region", "us-west-2", "host"}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, testFlags{Verbose: true, Region: "us-west-2"}, opts)
+	assert.Equal(t, []string{"host"}, positional)
+}
+
+func TestParseAs_ParseError(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := ParseAs[testFlags]([]string{"--unknown"}, nil)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrParse)
+}
+
+func TestSiftAs(t *testing.T) {
+	t.Parallel()
+
+	opts, remaining, positional, err := SiftAs[testEmbedded](
+		[]string{"-r", "us-west-2", "-o", "opt", "-p", "prof", "host"},
+		[]string{"-o"},
+		&Config{StopAtFirstPositional: true},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", opts.Region)
+	assert.Equal(t, "prof", opts.Profile)
+	assert.Equal(t, []string{"-o", "opt"}, remaining)
+	assert.Equal(t, []string{"host"}, positional)
+}
+
+func TestParseAs_SchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	type badStruct struct {
+		Count float64 `short:"c"`
+	}
+
+	type multiChar struct {
+		NoCheck bool `short:"nc"`
+	}
+
+	_, _, err := ParseAs[badStruct](nil, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSchema)
+	assert.NotErrorIs(t, err, ErrParse)
+
+	_, _, err = ParseAs[*testFlags](nil, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSchema)
+
+	_, _, _, err = SiftAs[multiChar](nil, nil, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSchema)
+
+	_, _, _, err = SiftAs[multiChar](nil, nil, &Config{MultiCharShort: true})
+	require.NoError(t, err)
+}
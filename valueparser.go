@@ -0,0 +1,53 @@
+package argsieve
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueParser parses the raw value of a flag into a value for its field,
+// for types argsieve cannot parse itself or to parse a type differently
+// (a comma-separated list, hex bytes, a regular expression). The result
+// must be assignable to the field; nil sets the field to its zero value.
+// Boolean flags are passed an empty value.
+//
+// Register parsers in [Config.TypeParsers] or [Config.FieldParsers].
+type ValueParser func(value string) (any, error)
+
+// valueParser returns the parser cfg registers for spec's field, by field
+// path first and by type second. cfg may be nil.
+func (cfg *Config) valueParser(spec *fieldSpec) (ValueParser, bool) {
+	if cfg == nil {
+		return nil, false
+	}
+
+	if parse, ok := cfg.FieldParsers[spec.name]; ok {
+		return parse, true
+	}
+
+	parse, ok := cfg.TypeParsers[spec.typ]
+
+	return parse, ok
+}
+
+// setParsed assigns the result of a ValueParser to a field.
+func setParsed(info fieldInfo, parse ValueParser, value string) error {
+	v, err := parse(value)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		info.field.SetZero()
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(info.typ) {
+		return fmt.Errorf("parser returned %T, want %s", v, info.typ)
+	}
+
+	info.field.Set(rv)
+
+	return nil
+}
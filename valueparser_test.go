@@ -0,0 +1,216 @@
+package argsieve
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parserFlags has fields that need a ValueParser.
+type parserFlags struct {
+	Tags    []string       `long:"tags"`
+	Key     []byte         `long:"key"`
+	Match   *regexp.Regexp `long:"match"`
+	Proxy   *url.URL       `long:"proxy"`
+	Count   int            `short:"c"`
+	Name    string         `short:"n"`
+	Verbose bool           `short:"v"`
+}
+
+// splitComma parses a comma-separated list.
+func splitComma(value string) (any, error) {
+	return strings.Split(value, ","), nil
+}
+
+func TestConfig_ValueParsers(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		TypeParsers: map[reflect.Type]ValueParser{
+			reflect.TypeFor[[]string](): splitComma,
+			reflect.TypeFor[int](): func(value string) (any, error) {
+				return len(value), nil
+			},
+			reflect.TypeFor[string](): func(value string) (any, error) {
+				return strings.ToUpper(value), nil
+			},
+		},
+		FieldParsers: map[string]ValueParser{
+			"Key": func(value string) (any, error) {
+				return hex.DecodeString(value)
+			},
+			"Match": func(value string) (any, error) {
+				return regexp.Compile("(?i)" + value)
+			},
+			"Proxy": func(value string) (any, error) {
+				return url.Parse(value)
+			},
+			"Name": func(value string) (any, error) {
+				return "field:" + value, nil
+			},
+		},
+	}
+
+	var opts parserFlags
+	positional, err := Parse(&opts, []string{
+		"--tags=a,b", "--key", "cafe", "--match", "^x", "--proxy", "http://p:3128", "-c", "abc", "-n", "x", "f",
+	}, cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f"}, positional)
+	assert.Equal(t, []string{"a", "b"}, opts.Tags)
+	assert.Equal(t, []byte{0xca, 0xfe}, opts.Key)
+	assert.Equal(t, "(?i)^x", opts.Match.String(), "field parser takes precedence over UnmarshalText")
+	assert.Equal(t, "p:3128", opts.Proxy.Host)
+	assert.Equal(t, 3, opts.Count)
+	assert.Equal(t, "field:x", opts.Name, "field parser takes precedence")
+}
+
+func TestConfig_ValueParsers_Errors(t *testing.T) {
+	t.Parallel()
+
+	errBad := errors.New("bad value")
+
+	tests := map[string]struct {
+		parser  ValueParser
+		wantErr string
+	}{
+		"parser error": {
+			parser:  func(string) (any, error) { return nil, errBad },
+			wantErr: "invalid value for --tags: bad value",
+		},
+		"wrong type": {
+			parser:  func(string) (any, error) { return 42, nil },
+			wantErr: "invalid value for --tags: parser returned int, want []string",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{FieldParsers: map[string]ValueParser{
+				"Tags": tt.parser, "Key": splitComma, "Proxy": splitComma, "Count": splitComma,
+			}}
+
+			var opts parserFlags
+			_, err := Parse(&opts, []string{"--tags", "x"}, cfg)
+
+			require.ErrorIs(t, err, ErrParse)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_ValueParsers_NilResult(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{TypeParsers: map[reflect.Type]ValueParser{
+		reflect.TypeFor[[]string](): func(string) (any, error) { return nil, nil },
+		reflect.TypeFor[[]byte]():   splitComma,
+		reflect.TypeFor[*url.URL](): splitComma,
+		reflect.TypeFor[int]():      splitComma,
+	}}
+
+	opts := parserFlags{Tags: []string{"old"}}
+	_, err := Parse(&opts, []string{"--tags", "x"}, cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, opts.Tags)
+}
+
+func TestValidate_ValueParsers(t *testing.T) {
+	t.Parallel()
+
+	all := map[reflect.Type]ValueParser{
+		reflect.TypeFor[[]string](): splitComma,
+		reflect.TypeFor[[]byte]():   splitComma,
+		reflect.TypeFor[*url.URL](): splitComma,
+		reflect.TypeFor[int]():      splitComma,
+	}
+
+	tests := map[string]struct {
+		cfg      *Config
+		wantErrs []string
+	}{
+		"no parsers": {
+			cfg: nil,
+			wantErrs: []string{
+				"field Tags has unsupported type []string",
+				"field Key has unsupported type []uint8",
+				"pointer field Proxy must point to type implementing encoding.TextUnmarshaler",
+				"field Count has unsupported type int",
+			},
+		},
+		"type parsers": {
+			cfg: &Config{TypeParsers: all},
+		},
+		"field parsers": {
+			cfg: &Config{FieldParsers: map[string]ValueParser{
+				"Tags": splitComma, "Key": splitComma, "Proxy": splitComma, "Count": splitComma,
+			}},
+		},
+		"unknown field": {
+			cfg:      &Config{TypeParsers: all, FieldParsers: map[string]ValueParser{"Nope": splitComma}},
+			wantErrs: []string{"FieldParsers names unknown field Nope"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate((*parserFlags)(nil), tt.cfg)
+
+			if len(tt.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrSchema)
+			assert.Len(t, strings.Split(err.Error(), "\n"), len(tt.wantErrs))
+			for _, want := range tt.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestParser_ValueParsersCopied(t *testing.T) {
+	t.Parallel()
+
+	parsers := map[reflect.Type]ValueParser{
+		reflect.TypeFor[[]string](): splitComma,
+		reflect.TypeFor[[]byte]():   splitComma,
+		reflect.TypeFor[*url.URL](): splitComma,
+		reflect.TypeFor[int]():      splitComma,
+	}
+	p := NewParser((*parserFlags)(nil), &Config{TypeParsers: parsers})
+
+	delete(parsers, reflect.TypeFor[[]string]())
+
+	var opts parserFlags
+	_, err := p.Parse(&opts, []string{"--tags=a,b"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, opts.Tags)
+}
+
+func TestMarshal_ValueParserType(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{FieldParsers: map[string]ValueParser{
+		"Tags": splitComma, "Key": splitComma, "Proxy": splitComma, "Count": splitComma,
+	}}
+
+	_, err := Marshal(&parserFlags{Tags: []string{"a"}}, cfg)
+
+	assert.ErrorContains(t, err, "type []string does not implement encoding.TextMarshaler")
+}